@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/modbussim"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// modbussim runs a modbus simulator for demos, without a config it serves one tcp device on port 10502, the port the
+// modbus network uses by default
+func main() {
+	configPath := flag.String("config", "", "path to a json config, see modbussim.Config")
+	address := flag.String("address", "", "listen address, overrides the config")
+	framing := flag.String("framing", "", "tcp or rtu, overrides the config")
+	printConfig := flag.Bool("print", false, "print the config and exit, a starting point for a config file")
+	flag.Parse()
+
+	config := modbussim.DefaultConfig()
+	if *configPath != "" {
+		var err error
+		config, err = modbussim.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *address != "" {
+		config.Address = *address
+	}
+	if *framing != "" {
+		config.Framing = modbussim.Framing(*framing)
+	}
+	if *printConfig {
+		b, _ := json.MarshalIndent(config, "", "  ")
+		fmt.Println(string(b))
+		return
+	}
+
+	server, err := config.Server()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := server.Listen(config.Address); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("modbus %s simulator listening on %s with %d devices", config.Framing, server.Addr(), len(config.Devices))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	if err := server.Close(); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/comparison"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+)
+
+var Equal comparisonEqualObject
+var NotEqual comparisonNotEqualObject
+var Greater comparisonGreaterObject
+var GreaterOrEqual comparisonGreaterOrEqualObject
+var Less comparisonLessObject
+var LessOrEqual comparisonLessOrEqualObject
+var Between comparisonBetweenObject
+var Outside comparisonOutsideObject
+
+func init() {
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.Equal, export: "Equal", node: &Equal})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.NotEqual, export: "NotEqual", node: &NotEqual})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.Greater, export: "Greater", node: &Greater})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.GreaterOrEqual, export: "GreaterOrEqual", node: &GreaterOrEqual})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.Less, export: "Less", node: &Less})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.LessOrEqual, export: "LessOrEqual", node: &LessOrEqual})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.Between, export: "Between", node: &Between})
+	registerNode(nodeRegistration{category: categoryComparison, name: comparison.Outside, export: "Outside", node: &Outside})
+}
+
+var comparisonOutput = ports.Bool(constants.Output)
+var comparisonValue = ports.Any(constants.Value)
+
+type comparisonSettings struct {
+	comparison.Config
+	Value      any `json:"value"`      // compared against until input-2 has a value
+	Min        any `json:"min"`        // used until the min input has a value
+	Max        any `json:"max"`        // used until the max input has a value
+	TrueValue  any `json:"trueValue"`  // sent on the value output when true, if not set the input value is sent
+	FalseValue any `json:"falseValue"` // sent on the value output when false, if not set the input value is sent
+}
+
+// comparisonSchema builds the schema for an operation, between and outside have a min and max instead of a value
+func comparisonSchema(title string, isRange bool) *schemas.Schema {
+	s := schemas.New(title)
+	s.Enum("type", "Compare as", comparison.TypeAuto, comparison.TypeAuto, comparison.TypeFloat, comparison.TypeString, comparison.TypeBool).
+		Names("Auto", "Number", "Text", "Bool")
+	if isRange {
+		s.Any("min", "Min").Describe("used until the min input has a value")
+		s.Any("max", "Max").Describe("used until the max input has a value")
+	} else {
+		s.Any("value", "Value").Describe("compared against until input-2 has a value")
+	}
+	s.Number("hysteresis", "Hysteresis", 0).AtLeast(0)
+	s.Number("tolerance", "Tolerance", 0).AtLeast(0).Describe("numbers within the tolerance are equal")
+	s.Bool("ignoreCase", "Ignore case", false).When("type", comparison.TypeAuto, comparison.TypeString)
+	s.Any("trueValue", "True value").Describe("sent on the value output when true, if not set the input value is sent")
+	s.Any("falseValue", "False value").Describe("sent on the value output when false, if not set the input value is sent")
+	return s
+}
+
+var comparisonValueSchema = comparisonSchema("Comparison", false)
+var comparisonRangeSchema = comparisonSchema("Range comparison", true)
+
+// comparisonObject compares input-1 to input-2, or for between and outside the input to the min and max
+// it outputs the result and a value selected by the result, nothing is sent until all the values are known
+type comparisonObject struct {
+	rxlib.Object
+	settings   *comparisonSettings
+	comparator *comparison.Comparator
+	inputIDs   []string
+	values     map[string]any
+	lastError  string
+	loaded     *loadedSettings
+	stop       chan struct{}
+}
+
+func newComparisonObject(operation, objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) comparisonObject {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(operation, objectUUID, name, pluginName), bus)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryComparison,
+	})
+	var inputIDs []string
+	settingsSchema := comparisonValueSchema
+	if comparison.IsRange(operation) {
+		inputIDs = []string{constants.Input, constants.Min, constants.Max}
+		settingsSchema = comparisonRangeSchema
+	} else {
+		inputIDs = []string{constants.Input1, constants.Input2}
+	}
+	for _, id := range inputIDs {
+		ports.AddInputs(object, ports.Any(id))
+	}
+	ports.AddOutputs(object, comparisonOutput, comparisonValue, errorOutput)
+	s := &comparisonSettings{}
+	loaded := loadSettings(object, settingsSchema, settings, s)
+	s.Operation = operation
+	comparator, err := comparison.New(s.Config)
+	if err != nil {
+		object.AddValidationResult("settings", err.Error())
+	} else if comparison.IsRange(operation) && s.Min != nil && s.Max != nil {
+		if err := comparator.CheckRange(s.Min, s.Max); err != nil {
+			object.AddValidationResult("settings.hysteresis", err.Error())
+			comparator = nil
+		}
+	}
+	values := make(map[string]any)
+	for id, value := range map[string]any{constants.Input2: s.Value, constants.Min: s.Min, constants.Max: s.Max} {
+		if value != nil {
+			values[id] = value
+		}
+	}
+	return comparisonObject{
+		Object:     object,
+		settings:   s,
+		comparator: comparator,
+		inputIDs:   inputIDs,
+		values:     values,
+		loaded:     loaded,
+		stop:       make(chan struct{}),
+	}
+}
+
+func (n *comparisonObject) CallSchema() *schema.Generated {
+	return n.loaded.generated()
+}
+
+// UpdateSettings checks the new settings, the comparator is built on create so a change needs a restart
+func (n *comparisonObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *comparisonObject) Start() {
+	if n.Loaded() || n.comparator == nil {
+		return
+	}
+	n.SetLoaded(true)
+	inputs := mergeInputs(n, n.inputIDs, n.stop)
+	go func() {
+		for {
+			select {
+			case <-n.stop:
+				return
+			case in := <-inputs:
+				n.handleInput(in)
+				in.done()
+			}
+		}
+	}()
+}
+
+func (n *comparisonObject) handleInput(in inputMessage) {
+	n.values[in.inputID] = messageValue(in.message)
+	args := make([]any, len(n.inputIDs))
+	for i, id := range n.inputIDs {
+		value, ok := n.values[id]
+		if !ok {
+			return
+		}
+		args[i] = value
+	}
+	var result bool
+	var err error
+	if len(args) == 3 {
+		result, err = n.comparator.CompareRange(args[0], args[1], args[2])
+	} else {
+		result, err = n.comparator.Compare(args[0], args[1])
+	}
+	if err != nil {
+		n.setError(err.Error())
+		return
+	}
+	n.setError("")
+	selected := args[0]
+	if result && n.settings.TrueValue != nil {
+		selected = n.settings.TrueValue
+	}
+	if !result && n.settings.FalseValue != nil {
+		selected = n.settings.FalseValue
+	}
+	publishOutput(n, comparisonOutput, result)
+	publishOutput(n, comparisonValue, selected)
+}
+
+// setError publishes the error when it changes, an empty string clears the error
+func (n *comparisonObject) setError(message string) {
+	if message == n.lastError {
+		return
+	}
+	n.lastError = message
+	publishOutput(n, errorOutput, message)
+}
+
+func (n *comparisonObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
+type comparisonEqualObject struct {
+	comparisonObject
+}
+
+func (n *comparisonEqualObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonEqualObject{newComparisonObject(comparison.Equal, objectUUID, name, bus, settings)}
+}
+
+type comparisonNotEqualObject struct {
+	comparisonObject
+}
+
+func (n *comparisonNotEqualObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonNotEqualObject{newComparisonObject(comparison.NotEqual, objectUUID, name, bus, settings)}
+}
+
+type comparisonGreaterObject struct {
+	comparisonObject
+}
+
+func (n *comparisonGreaterObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonGreaterObject{newComparisonObject(comparison.Greater, objectUUID, name, bus, settings)}
+}
+
+type comparisonGreaterOrEqualObject struct {
+	comparisonObject
+}
+
+func (n *comparisonGreaterOrEqualObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonGreaterOrEqualObject{newComparisonObject(comparison.GreaterOrEqual, objectUUID, name, bus, settings)}
+}
+
+type comparisonLessObject struct {
+	comparisonObject
+}
+
+func (n *comparisonLessObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonLessObject{newComparisonObject(comparison.Less, objectUUID, name, bus, settings)}
+}
+
+type comparisonLessOrEqualObject struct {
+	comparisonObject
+}
+
+func (n *comparisonLessOrEqualObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonLessOrEqualObject{newComparisonObject(comparison.LessOrEqual, objectUUID, name, bus, settings)}
+}
+
+type comparisonBetweenObject struct {
+	comparisonObject
+}
+
+func (n *comparisonBetweenObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonBetweenObject{newComparisonObject(comparison.Between, objectUUID, name, bus, settings)}
+}
+
+type comparisonOutsideObject struct {
+	comparisonObject
+}
+
+func (n *comparisonOutsideObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &comparisonOutsideObject{newComparisonObject(comparison.Outside, objectUUID, name, bus, settings)}
+}
@@ -1,8 +1,49 @@
 package constants
 
+import "fmt"
+
 const Input = "input"
 const Input1 = "input-1"
 const Input2 = "input-2"
 const Input3 = "input-3"
 
 const Output = "output"
+
+const Reachable = "reachable"
+const Latency = "latency"
+const Loss = "loss"
+
+const Enable = "enable"
+const Fire = "fire"
+
+const Value = "value"
+const Next = "next"
+
+const Set = "set"
+const Reset = "reset"
+
+const Preset = "preset"
+
+const Count = "count"
+const Sum = "sum"
+const Min = "min"
+const Max = "max"
+const Mean = "mean"
+const StdDev = "std-dev"
+const Rate = "rate"
+const AvgMessageCount = "avg-message-count"
+
+const ProcessValue = "process-value"
+const Setpoint = "setpoint"
+const Auto = "auto"
+const Manual = "manual"
+
+const Error = "error"
+
+const Active = "active"
+const Status = "status"
+
+// InputName returns the id of a numbered input, eg; InputName(1) is Input1
+func InputName(n int) string {
+	return fmt.Sprintf("%s-%d", Input, n)
+}
@@ -4,27 +4,98 @@ import (
 	"fmt"
 	"github.com/NubeIO/reactive"
 	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/reactive-nodes/helpers/persist"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/counter"
 	"github.com/NubeIO/rxlib"
 	"github.com/NubeIO/schema"
+	"time"
 )
 
 var Count countObject
 
-// countObject represents a object that counts incoming messages and sends out the count value.
+const countName = "count"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryCount, name: countName, export: "Count", node: &Count})
+}
+
+var countInput = ports.Any(constants.Input)
+var countReset = ports.Bool(constants.Reset)
+var countPreset = ports.Float(constants.Preset)
+var countOutput = ports.Float(constants.Output)
+
+// countSaveDelay is how long a changed count waits before it is saved, so a busy input does not write on every message
+const countSaveDelay = 5 * time.Second
+
+// countObject counts incoming messages and sends out the count value, the count is saved so it survives a restart
 type countObject struct {
 	rxlib.Object
-	count int
+	settings    *countObjectSettings
+	counter     *counter.Counter
+	store       persist.Store
+	saveDelay   time.Duration
+	saveTimer   *time.Timer
+	savePending bool
+	loaded      *loadedSettings
+	loop        *objectLoop
+	stop        chan struct{}
+}
+
+type countObjectSettings struct {
+	counter.Config
+	Persist bool `json:"persist"` // save the count after it changes and load it on start
+}
+
+var countSchema = func() *schemas.Schema {
+	s := schemas.New("Count")
+	s.Integer("startCount", "Start count", 0).Live().Describe("the count after a reset")
+	s.Integer("step", "Step", 1).AtLeast(1).Live()
+	s.Enum("direction", "Direction", string(counter.Up), string(counter.Up), string(counter.Down)).Names("Up", "Down").Live()
+	s.Integer("min", "Min", 0).Optional().Live().Describe("leave empty for no lower limit")
+	s.Integer("max", "Max", 0).Optional().Live().Describe("leave empty for no upper limit")
+	s.Bool("rollover", "Rollover", false).Live().Describe("wrap to the other limit instead of holding at the limit")
+	s.Bool("risingEdge", "Rising edge", false).Live().Describe("only count when the input goes from false to true")
+	s.Bool("persist", "Persist", true).Live().Describe("save the count a few seconds after it changes and load it on start")
+	return s
+}()
+
+func defaultCountSettings() *countObjectSettings {
+	return &countObjectSettings{
+		Config: counter.Config{
+			Step:      1,
+			Direction: counter.Up,
+		},
+		Persist: true,
+	}
 }
 
 // NewCountObject creates a new countObject with the given ID, name, and EventBus.
 func NewCountObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
-	object := reactive.NewBaseObject(reactive.ObjectInfo(count, objectUUID, name, pluginName), bus)
-	object.NewInputPort(constants.Input, constants.Input, "any")
-	object.NewOutputPort(constants.Output, constants.Output, "float")
+	object := reactive.NewBaseObject(reactive.ObjectInfo(countName, objectUUID, name, pluginName), bus)
+	ports.AddInputs(object, countInput, countReset, countPreset)
+	ports.AddOutputs(object, countOutput)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryCount,
+	})
 	object.SetHotFix()
+	s := defaultCountSettings()
+	loaded := loadSettings(object, countSchema, settings, s)
+	c, err := counter.New(s.Config)
+	if err != nil {
+		object.AddValidationResult("settings", err.Error())
+	}
 	return &countObject{
-		Object: object,
-		count:  0,
+		Object:    object,
+		settings:  s,
+		counter:   c,
+		store:     persist.NewFileStore(""),
+		saveDelay: countSaveDelay,
+		loaded:    loaded,
+		loop:      newObjectLoop(),
+		stop:      make(chan struct{}),
 	}
 }
 
@@ -34,88 +105,177 @@ func (n *countObject) New(objectUUID, name string, bus *rxlib.EventBus, settings
 	return newObject
 }
 
-func (n *countObject) Start() {
-	if n.NotLoaded() {
-		n.SetLoaded(true)
-		inputChannel, exists := n.BusChannel(constants.Input)
+func (n *countObject) persistKey() string {
+	return fmt.Sprintf("%s-%s", countName, n.GetUUID())
+}
 
-		if !exists {
-			fmt.Printf("Input channel for target input %s does not exist\n", constants.Input)
-			return
-		}
+// Start runs the object even if the settings are invalid, messages are dropped until an update builds the counter
+func (n *countObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputChannel, exists := n.BusChannel(constants.Input)
+	if !exists {
+		objectLog(n).Error("input channel does not exist", "port", constants.Input)
+		return
+	}
+	resetChannel, _ := n.BusChannel(constants.Reset)
+	presetChannel, _ := n.BusChannel(constants.Preset)
+	n.saveTimer = time.NewTimer(n.saveDelay)
+	n.saveTimer.Stop()
+	if n.counter != nil {
+		n.restore()
+		n.publish()
+	}
+	exit := n.loop.run()
+	go func() {
+		defer exit()
+		defer n.saveTimer.Stop()
+		// a count still waiting to be saved is written before the goroutine returns
+		defer func() {
+			if n.savePending {
+				n.save()
+			}
+		}()
 		for {
-			_, ok := <-inputChannel
-			if !ok {
+			select {
+			case <-n.stop:
 				return
+			case <-n.saveTimer.C:
+				n.save()
+			case apply := <-n.loop.updates:
+				apply()
+			case msg, ok := <-inputChannel:
+				if !ok {
+					return
+				}
+				done := received(n, constants.Input)
+				if n.counter == nil {
+					done()
+					continue
+				}
+				// any message is a count unless counting rising edges
+				var value bool
+				if n.counter.RisingEdge() {
+					value = inputBool(n, constants.Input, messageValue(msg))
+				}
+				if n.counter.Input(value) {
+					n.publish()
+				}
+				done()
+			case msg, ok := <-resetChannel:
+				if !ok {
+					resetChannel = nil
+					continue
+				}
+				done := received(n, constants.Reset)
+				if n.counter != nil && inputBool(n, constants.Reset, messageValue(msg)) {
+					n.counter.Reset()
+					n.publish()
+				}
+				done()
+			case msg, ok := <-presetChannel:
+				if !ok {
+					presetChannel = nil
+					continue
+				}
+				done := received(n, constants.Preset)
+				if n.counter == nil {
+					done()
+					continue
+				}
+				preset, err := convert.ToInt(messageValue(msg))
+				if err != nil {
+					objectLog(n).Warn("preset is not a whole number", "port", constants.Preset, "value", messageValue(msg), "err", err)
+					done()
+					continue
+				}
+				n.counter.Set(preset)
+				n.publish()
+				done()
 			}
-			// Increment the count for each incoming message
-			n.count++
-			// Create a Port with the count value and send it to the output
-			countPort := &rxlib.Port{
-				ID:        constants.Output,
-				Name:      constants.Output,
-				Value:     float64(n.count), // Convert count to float64
-				Direction: "output",
-				DataType:  "float", // Data type of the output
-			}
-			n.PublishMessage(countPort, true)
 		}
-	}
-
+	}()
 }
 
-type countObjectSettings struct {
-	StartCount int
+// restore sets the counter to the saved count
+func (n *countObject) restore() {
+	if !n.settings.Persist {
+		return
+	}
+	var saved int
+	found, err := n.store.Load(n.persistKey(), &saved)
+	if err != nil {
+		n.AddValidationResult("persist", fmt.Sprintf("failed to load saved count: %v", err))
+	} else if found {
+		n.counter.Set(saved)
+	}
 }
 
-func (n *countObject) CallSchema() *schema.Generated {
-
-	//builder := schema.NewSchemaBuilder("HEY")
-	//
-	//builder.NewString("exampleString", "Example String", true, 3, 10, "default value")
-	//builder.NewNumber("exampleNumber", "Example Num", true, nil, nil, 11)
-	//
-	//ui := schema.UI{}
-	//ui.AddUIOrder([]string{"exampleString", "exampleNumber"})
-	//out := &schema.Generated{
-	//	schema: builder.Build(),
-	//	UI:     ui,
-	//}
-
-	builder := schema.NewSchemaBuilder("IF/THEN").
-		SetProperty("holidayType", schema.Property{Type: "string", Enum: []string{"snow", "beach"}})
-
-	newCon := make(map[string]schema.Property)
-	aa := 8.0
-	newCon["a"] = schema.NewNumber("want a new aa", false, nil, &aa, 1)
-	newCon["b"] = schema.NewNumber("want a new bb", false, nil, &aa, 1)
-
-	builder.AddCondition(schema.ConditionalStructure{
-		If: schema.Condition{
-			Properties: map[string]schema.Property{"holidayType": {Const: "beach"}},
-		},
-		Then: schema.Condition{
-			Properties: newCon,
-		},
-	})
+// publish sends the count to the output and schedules a save, the saves of a count that changes often are batched
+func (n *countObject) publish() {
+	if n.settings.Persist && !n.savePending && n.saveTimer != nil {
+		n.savePending = true
+		n.saveTimer.Reset(n.saveDelay)
+	}
+	publishOutput(n, countOutput, n.counter.Count())
+}
 
-	newCon2 := make(map[string]schema.Property)
+// save writes the count, it runs on the object goroutine once the save delay has passed
+func (n *countObject) save() {
+	n.savePending = false
+	if !n.settings.Persist {
+		return
+	}
+	if err := n.store.Save(n.persistKey(), n.counter.Count()); err != nil {
+		n.AddValidationResult("persist", fmt.Sprintf("failed to save count: %v", err))
+	}
+}
 
-	newCon2["a"] = schema.NewString("want a new aa str", false, 1, 100, "hey")
-	newCon2["bb"] = newCon["b"]
+// Delete removes the saved count so a new object with the same uuid starts from its start count, it waits for the
+// goroutine so a pending save can not write the count back
+func (n *countObject) Delete() {
+	close(n.stop)
+	n.loop.wait()
+	if err := n.store.Delete(n.persistKey()); err != nil {
+		objectLog(n).Warn("failed to remove the saved count", "err", err)
+	}
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
 
-	// Dynamic condition for 'snow' holiday
-	builder.AddCondition(schema.ConditionalStructure{
-		If: schema.Condition{
-			Properties: map[string]schema.Property{"holidayType": {Const: "snow"}},
-		},
-		Then: schema.Condition{
-			Properties: newCon2,
-		},
-	})
+func (n *countObject) CallSchema() *schema.Generated {
+	return countSchema.Generated()
+}
 
-	out := &schema.Generated{
-		Schema: builder.Build(),
+// UpdateSettings applies new settings while the object runs, the count is kept and held within the new limits
+func (n *countObject) UpdateSettings(settings *rxlib.Settings) {
+	next, ok := n.loaded.update(n.Object, settings)
+	if !ok {
+		return
 	}
-	return out
+	s := next.(*countObjectSettings)
+	n.loop.apply(func() {
+		if n.counter == nil {
+			c, err := counter.New(s.Config)
+			if err != nil {
+				n.AddValidationResult("settings", err.Error())
+				return
+			}
+			n.counter = c
+			n.settings = s
+			if n.Loaded() {
+				n.restore()
+			}
+		} else if err := n.counter.Configure(s.Config); err != nil {
+			n.AddValidationResult("settings", err.Error())
+			return
+		}
+		n.settings = s
+		if n.Loaded() {
+			n.publish()
+		}
+	})
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/logger"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"github.com/NubeIO/reactive-nodes/helpers/persist"
+	"github.com/NubeIO/rxlib"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCount(t *testing.T) {
+	h := nodetest.New(t, &Count, map[string]any{"max": 2, "persist": false}).Start()
+	h.Expect(constants.Output, 0.0)
+	for _, expected := range []float64{1, 2} {
+		h.Send(constants.Input, true)
+		h.Expect(constants.Output, expected)
+	}
+	// held at the max, nothing changes
+	h.Send(constants.Input, true)
+	h.Send(constants.Reset, true)
+	h.Expect(constants.Output, 0.0)
+	h.Send(constants.Preset, 7)
+	h.Expect(constants.Output, 2.0)
+
+	// a bad preset is logged against the object
+	h.Send(constants.Preset, "x")
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	log, ok := logger.Default.Get(h.Object.GetUUID())
+	if !ok {
+		t.Fatal("expected the object to have logged")
+	}
+	if recent := log.Recent(); len(recent) == 0 || recent[len(recent)-1].Attrs["port"] != constants.Preset {
+		t.Errorf("expected the bad preset to be logged, got %+v", recent)
+	}
+}
+
+func TestCountAnyMessage(t *testing.T) {
+	h := nodetest.New(t, &Count, map[string]any{"persist": false}).Start()
+	h.Expect(constants.Output, 0.0)
+	h.Send(constants.Input, "pulse")
+	h.Expect(constants.Output, 1.0)
+	h.Send(constants.Input, 12.5)
+	h.Expect(constants.Output, 2.0)
+	// only counting rising edges needs a bool, so nothing is logged
+	if log, ok := logger.Default.Get(h.Object.GetUUID()); ok {
+		for _, line := range log.Recent() {
+			if line.Attrs["port"] == constants.Input {
+				t.Errorf("Expected: no log for the input, Got: %+v", line)
+			}
+		}
+	}
+}
+
+// countingStore counts the saves made to the store it wraps and keeps the last count saved
+type countingStore struct {
+	persist.Store
+	saves atomic.Int32
+	last  atomic.Int64
+}
+
+func (s *countingStore) Save(key string, value any) error {
+	s.saves.Add(1)
+	if count, ok := value.(int); ok {
+		s.last.Store(int64(count))
+	}
+	return s.Store.Save(key, value)
+}
+
+func TestCountPersist(t *testing.T) {
+	h := nodetest.New(t, &Count, nil)
+	n := h.Object.(*countObject)
+	store := &countingStore{Store: persist.NewFileStore(t.TempDir())}
+	n.store = store
+	n.saveDelay = 50 * time.Millisecond
+	h.Start()
+	h.Expect(constants.Output, 0.0)
+	for _, expected := range []float64{1, 2, 3} {
+		h.Send(constants.Input, true)
+		h.Expect(constants.Output, expected)
+	}
+	var saved int
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if found, _ := store.Load(n.persistKey(), &saved); found && saved == 3 {
+			break
+		}
+	}
+	if saved != 3 {
+		t.Fatalf("Expected: 3 to be saved, Got: %d", saved)
+	}
+	if saves := store.saves.Load(); saves > 2 {
+		t.Errorf("expected the saves to be batched, got %d saves", saves)
+	}
+
+	h.Stop()
+	if found, _ := store.Load(n.persistKey(), &saved); found {
+		t.Error("expected Delete to remove the saved count")
+	}
+}
+
+func TestCountSaveOnStop(t *testing.T) {
+	h := nodetest.New(t, &Count, nil)
+	n := h.Object.(*countObject)
+	store := &countingStore{Store: persist.NewFileStore(t.TempDir())}
+	n.store = store
+	n.saveDelay = time.Hour
+	h.Start()
+	h.Expect(constants.Output, 0.0)
+	for _, expected := range []float64{1, 2} {
+		h.Send(constants.Input, true)
+		h.Expect(constants.Output, expected)
+	}
+	// the save is still waiting on the delay when the object stops
+	h.Stop()
+	if saves, last := store.saves.Load(), store.last.Load(); saves != 1 || last != 2 {
+		t.Errorf("Expected: 1 save of 2, Got: %d saves of %d", saves, last)
+	}
+}
+
+func TestCountStartsWithInvalidSettings(t *testing.T) {
+	h := nodetest.New(t, &Count, map[string]any{"min": 5, "max": 1, "persist": false}).Start()
+	h.Send(constants.Input, true)
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	h.Object.(*countObject).UpdateSettings(&rxlib.Settings{Value: map[string]any{"persist": false}})
+	h.Expect(constants.Output, 0.0)
+	h.Send(constants.Input, true)
+	h.Expect(constants.Output, 1.0)
+}
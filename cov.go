@@ -0,0 +1,112 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/trigger"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+)
+
+var ChangeOfValue changeOfValueObject
+
+const changeOfValueName = "change-of-value"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryStream, name: changeOfValueName, export: "ChangeOfValue", node: &ChangeOfValue})
+}
+
+var changeOfValueInput = ports.Any(constants.Input)
+var changeOfValueReset = ports.Bool(constants.Reset)
+var changeOfValueOutput = ports.Any(constants.Output)
+
+type changeOfValueSettings struct {
+	Tolerance float64 `json:"tolerance"` // numbers must move by more than this to be passed, 0 passes any change
+}
+
+var changeOfValueSchema = func() *schemas.Schema {
+	s := schemas.New("Change of value")
+	s.Number("tolerance", "Tolerance", 0).AtLeast(0).Describe("numbers must move by more than this to be passed, 0 passes any change")
+	return s
+}()
+
+// changeOfValueObject only passes a value when it differs from the last value it passed
+type changeOfValueObject struct {
+	rxlib.Object
+	filter *trigger.ChangeOfValue
+	loaded *loadedSettings
+	stop   chan struct{}
+}
+
+func NewChangeOfValueObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(changeOfValueName, objectUUID, name, pluginName), bus)
+	ports.AddInputs(object, changeOfValueInput, changeOfValueReset)
+	ports.AddOutputs(object, changeOfValueOutput)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryStream,
+	})
+	s := &changeOfValueSettings{}
+	loaded := loadSettings(object, changeOfValueSchema, settings, s)
+	return &changeOfValueObject{
+		Object: object,
+		filter: trigger.NewChangeOfValue(s.Tolerance),
+		loaded: loaded,
+		stop:   make(chan struct{}),
+	}
+}
+
+func (n *changeOfValueObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewChangeOfValueObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+func (n *changeOfValueObject) CallSchema() *schema.Generated {
+	return changeOfValueSchema.Generated()
+}
+
+// UpdateSettings checks the new settings, the filter is built on create so a change needs a restart
+func (n *changeOfValueObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *changeOfValueObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputs := mergeInputs(n, []string{constants.Input, constants.Reset}, n.stop)
+	go func() {
+		for {
+			select {
+			case <-n.stop:
+				return
+			case in := <-inputs:
+				n.handleInput(in)
+				in.done()
+			}
+		}
+	}()
+}
+
+func (n *changeOfValueObject) handleInput(in inputMessage) {
+	value := messageValue(in.message)
+	if in.inputID == constants.Reset {
+		// the next value is passed whatever it is
+		if inputBool(n, constants.Reset, value) {
+			n.filter.Reset()
+		}
+		return
+	}
+	if n.filter.Update(value) {
+		publishOutput(n, changeOfValueOutput, value)
+	}
+}
+
+func (n *changeOfValueObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
@@ -4,7 +4,9 @@ import (
 	"github.com/NubeIO/reactive"
 	"github.com/NubeIO/reactive-nodes/constants"
 	dhcp "github.com/NubeIO/reactive-nodes/dhcpd"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
 	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"os"
@@ -12,6 +14,11 @@ import (
 
 var DHCP dhcpObject
 
+// dhcpName is not registered, the object is not finished
+const dhcpName = "dhcp"
+
+var dhcpOutput = ports.Bool(constants.Output)
+
 type dhcpObject struct {
 	rxlib.Object
 	dhcp     dhcp.DHCP
@@ -20,7 +27,7 @@ type dhcpObject struct {
 
 func NewDHCPObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
 	object := reactive.NewBaseObject(reactive.ObjectInfo(dhcpName, objectUUID, name, pluginName), bus)
-	object.NewOutputPort(constants.Output, constants.Output, "bool")
+	ports.AddOutputs(object, dhcpOutput)
 	object.SetDetails(&rxlib.Details{
 		Category:   categoryNetworkingDHCP,
 		ObjectType: rxlib.Service,
@@ -41,6 +48,10 @@ func (n *dhcpObject) New(objectUUID, name string, bus *rxlib.EventBus, settings
 	return newObject
 }
 
+func (n *dhcpObject) CallSchema() *schema.Generated {
+	return noSettingsSchema.Generated()
+}
+
 func (n *dhcpObject) Start() {
 	if n.NotLoaded() {
 
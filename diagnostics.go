@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/helpers/logger"
+	"github.com/NubeIO/reactive-nodes/helpers/metrics"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"github.com/gin-gonic/gin"
+)
+
+var Diagnostics diagnosticsObject
+
+const diagnosticsName = "diagnostics"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryDiagnostics, name: diagnosticsName, export: "Diagnostics", node: &Diagnostics})
+}
+
+// diagnosticsObject serves the logs and metrics of every object, the host adds its routes when it is created
+type diagnosticsObject struct {
+	rxlib.Object
+}
+
+func NewDiagnosticsObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(diagnosticsName, objectUUID, name, pluginName), bus)
+	object.SetDetails(&rxlib.Details{
+		Category:   categoryDiagnostics,
+		ObjectType: rxlib.Service,
+	})
+	object.AddObjectTypeRequirement(rxlib.RequirementMaxOne())
+	object.AddObjectTypeRequirement(rxlib.RequirementWebRouter())
+	return &diagnosticsObject{Object: object}
+}
+
+func (n *diagnosticsObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewDiagnosticsObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+func (n *diagnosticsObject) CallSchema() *schema.Generated {
+	return noSettingsSchema.Generated()
+}
+
+func (n *diagnosticsObject) Start() {
+	n.SetLoaded(true)
+}
+
+// NewRoute adds the routes under the plugin name, see logger.Registry.Routes and metrics.Registry.Routes
+func (n *diagnosticsObject) NewRoute(r *gin.RouterGroup) {
+	group := r.Group("/" + pluginName)
+	logger.Default.Routes(group)
+	metrics.Default.Routes(group)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/logger"
+	"github.com/NubeIO/reactive-nodes/helpers/metrics"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"github.com/NubeIO/rxlib"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestDiagnosticsRoutes checks the logs and metrics of an object are served until it is deleted
+func TestDiagnosticsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	Diagnostics.New("diagnostics", "diagnostics", rxlib.NewEventBus(), nil).(*diagnosticsObject).NewRoute(router.Group(""))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	h := nodetest.New(t, &Add, nil).Start()
+	objectLog(h.Object).Info("hello")
+	objectMetrics(h.Object).In(constants.Input1)
+	uuid := h.Object.GetUUID()
+	path := "/" + pluginName + "/logs/" + uuid
+	if w := get(path); w.Code != http.StatusOK {
+		t.Errorf("Expected: %d, Got: %d", http.StatusOK, w.Code)
+	}
+	if w := get("/" + pluginName + "/metrics"); !strings.Contains(w.Body.String(), `uuid="`+uuid+`"`) {
+		t.Errorf("expected metrics for %s, got %s", uuid, w.Body)
+	}
+	h.Stop()
+	if _, ok := logger.Default.Get(uuid); ok {
+		t.Error("expected the logger to be removed when the object is deleted")
+	}
+	if _, ok := metrics.Default.Get(uuid); ok {
+		t.Error("expected the metrics to be removed when the object is deleted")
+	}
+	if w := get(path); w.Code != http.StatusNotFound {
+		t.Errorf("Expected: %d, Got: %d", http.StatusNotFound, w.Code)
+	}
+}
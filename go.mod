@@ -11,12 +11,12 @@ require (
 	github.com/NubeIO/rxclient v0.0.3
 	github.com/NubeIO/rxlib v0.0.2
 	github.com/NubeIO/schema v0.0.1
+	github.com/NubeIO/unixclient v0.0.2
 	github.com/gin-gonic/gin v1.9.1
 	github.com/grid-x/modbus v0.0.0-20230713135356-d9fefd3ae5a5
 )
 
 require (
-	github.com/NubeIO/unixclient v0.0.2 // indirect
 	github.com/bytedance/sonic v1.10.2 // indirect
 	github.com/chenzhuoyu/base64x v0.0.0-20230717121745-296ad89f973d // indirect
 	github.com/chenzhuoyu/iasm v0.9.1 // indirect
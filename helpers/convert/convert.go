@@ -0,0 +1,332 @@
+package convert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	gomath "math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNil           = errors.New("value is not set")
+	ErrUnsupported   = errors.New("unsupported type")
+	ErrInvalidString = errors.New("invalid string")
+	ErrOutOfRange    = errors.New("out of range")
+	ErrFraction      = errors.New("has a fraction")
+	ErrNotFinite     = errors.New("not a finite number")
+)
+
+// Error is returned when a value can not be converted, use errors.Is with the Err values to check why
+type Error struct {
+	Value any
+	To    string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("can not convert to %s: %v", e.To, e.Err)
+	}
+	return fmt.Sprintf("can not convert %T %v to %s: %v", e.Value, e.Value, e.To, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// how ToBool treats values that are not bools
+const (
+	// TruthStrict only accepts bools and the true and false strings
+	TruthStrict = "strict"
+	// TruthNumbers also accepts numbers and numeric strings, anything but 0 is true
+	TruthNumbers = "numbers"
+	// TruthLoose is like numbers but any other value that is set is true, eg; "hello" or a map
+	TruthLoose = "loose"
+)
+
+// Rules change how values are converted, start from DefaultRules and change what is needed
+type Rules struct {
+	Truth        string   `json:"truth"`        // strict, numbers or loose
+	TrueStrings  []string `json:"trueStrings"`  // strings that are true, not case-sensitive
+	FalseStrings []string `json:"falseStrings"` // strings that are false, not case-sensitive
+	TrimSpace    bool     `json:"trimSpace"`    // trim spaces from strings before parsing them
+	EmptyIsZero  bool     `json:"emptyIsZero"`  // an empty string is 0 or false instead of an error
+	NilIsZero    bool     `json:"nilIsZero"`    // nil is 0, false or "" instead of an error
+	BoolNumbers  bool     `json:"boolNumbers"`  // bools convert to 1 and 0 in ToFloat and ToInt
+	HexStrings   bool     `json:"hexStrings"`   // allow 0x, 0o and 0b prefixed strings in ToFloat and ToInt
+	Truncate     bool     `json:"truncate"`     // ToInt drops a fraction instead of returning an error
+	Precision    int      `json:"precision"`    // digits after the point in ToString, -1 for as many as needed
+}
+
+// DefaultRules are the rules used by the package level functions
+func DefaultRules() Rules {
+	return Rules{
+		Truth:        TruthNumbers,
+		TrueStrings:  []string{"true", "on", "yes"},
+		FalseStrings: []string{"false", "off", "no"},
+		TrimSpace:    true,
+		BoolNumbers:  true,
+		HexStrings:   true,
+		Precision:    -1,
+	}
+}
+
+var defaultRules = DefaultRules()
+
+func ToFloat(value any) (float64, error) {
+	return defaultRules.ToFloat(value)
+}
+
+func ToBool(value any) (bool, error) {
+	return defaultRules.ToBool(value)
+}
+
+func ToInt(value any) (int, error) {
+	return defaultRules.ToInt(value)
+}
+
+func ToString(value any) (string, error) {
+	return defaultRules.ToString(value)
+}
+
+// ToFloat converts numbers, bools if BoolNumbers is set, numeric strings and json.Number to a float
+// NaN and infinity are rejected whether they are numbers or strings
+func (r Rules) ToFloat(value any) (float64, error) {
+	fail := func(err error) (float64, error) {
+		return 0, &Error{Value: value, To: "float", Err: err}
+	}
+	if value == nil {
+		if r.NilIsZero {
+			return 0, nil
+		}
+		return fail(ErrNil)
+	}
+	if f, ok := number(value); ok {
+		if gomath.IsNaN(f) || gomath.IsInf(f, 0) {
+			return fail(ErrNotFinite)
+		}
+		return f, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		if !r.BoolNumbers {
+			return fail(ErrUnsupported)
+		}
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		return r.parseFloat(value, string(v))
+	case string:
+		return r.parseFloat(value, v)
+	default:
+		return fail(ErrUnsupported)
+	}
+}
+
+// ToInt is ToFloat with a check that the value is a whole number, unless Truncate is set
+func (r Rules) ToInt(value any) (int, error) {
+	fail := func(err error) (int, error) {
+		return 0, &Error{Value: value, To: "int", Err: err}
+	}
+	// ints are not passed through a float so large values keep their precision
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		if int64(int(v)) != v {
+			return fail(ErrOutOfRange)
+		}
+		return int(v), nil
+	case uint64:
+		if v > gomath.MaxInt64 || uint64(int(v)) != v {
+			return fail(ErrOutOfRange)
+		}
+		return int(v), nil
+	case uint:
+		if uint64(v) > gomath.MaxInt64 || uint(int(v)) != v {
+			return fail(ErrOutOfRange)
+		}
+		return int(v), nil
+	}
+	f, err := r.ToFloat(value)
+	if err != nil {
+		var convertErr *Error
+		if errors.As(err, &convertErr) {
+			convertErr.To = "int"
+		}
+		return 0, err
+	}
+	if f != gomath.Trunc(f) {
+		if !r.Truncate {
+			return fail(ErrFraction)
+		}
+		f = gomath.Trunc(f)
+	}
+	if f >= -float64(minInt) || f < float64(minInt) {
+		return fail(ErrOutOfRange)
+	}
+	return int(f), nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+const minInt = -maxInt - 1
+
+// ToBool converts a value to a bool following Truth, the true and false strings are always accepted
+func (r Rules) ToBool(value any) (bool, error) {
+	fail := func(err error) (bool, error) {
+		return false, &Error{Value: value, To: "bool", Err: err}
+	}
+	if value == nil {
+		if r.NilIsZero {
+			return false, nil
+		}
+		return fail(ErrNil)
+	}
+	if v, ok := value.(bool); ok {
+		return v, nil
+	}
+	if s, ok := value.(string); ok {
+		if r.TrimSpace {
+			s = strings.TrimSpace(s)
+		}
+		for _, t := range r.TrueStrings {
+			if strings.EqualFold(s, t) {
+				return true, nil
+			}
+		}
+		for _, f := range r.FalseStrings {
+			if strings.EqualFold(s, f) {
+				return false, nil
+			}
+		}
+		if s == "" {
+			if r.EmptyIsZero {
+				return false, nil
+			}
+			return fail(ErrInvalidString)
+		}
+	}
+	if r.Truth == TruthStrict {
+		if _, ok := value.(string); ok {
+			return fail(ErrInvalidString)
+		}
+		return fail(ErrUnsupported)
+	}
+	f, err := r.ToFloat(value)
+	if err == nil {
+		return f != 0, nil
+	}
+	if r.Truth == TruthLoose {
+		return true, nil
+	}
+	if _, ok := value.(string); ok {
+		return fail(ErrInvalidString)
+	}
+	return fail(ErrUnsupported)
+}
+
+// ToString formats bools as true/false and numbers with Precision, strings, []byte and fmt.Stringer are passed as they are
+func (r Rules) ToString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		if r.NilIsZero {
+			return "", nil
+		}
+		return "", &Error{To: "string", Err: ErrNil}
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', r.Precision, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', r.Precision, 32), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', r.Precision, 64), nil
+	case reflect.String:
+		return rv.String(), nil
+	}
+	return "", &Error{Value: value, To: "string", Err: ErrUnsupported}
+}
+
+func (r Rules) parseFloat(value any, s string) (float64, error) {
+	if r.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if s == "" {
+		if r.EmptyIsZero {
+			return 0, nil
+		}
+		return 0, &Error{Value: value, To: "float", Err: ErrInvalidString}
+	}
+	if r.HexStrings && hasBasePrefix(s) {
+		i, err := strconv.ParseInt(s, 0, 64)
+		if err == nil {
+			return float64(i), nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, &Error{Value: value, To: "float", Err: ErrInvalidString}
+	}
+	if gomath.IsNaN(f) || gomath.IsInf(f, 0) {
+		return 0, &Error{Value: value, To: "float", Err: ErrNotFinite}
+	}
+	return f, nil
+}
+
+func hasBasePrefix(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	if len(s) < 2 || s[0] != '0' {
+		return false
+	}
+	switch s[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+	return false
+}
+
+// number returns the value of any int, uint or float type, including named types like a modbus register type
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
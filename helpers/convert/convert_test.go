@@ -0,0 +1,167 @@
+package convert
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+type register uint16
+
+func TestToFloat(t *testing.T) {
+	testCases := []struct {
+		value          any
+		expectedResult float64
+		expectErr      error
+	}{
+		{1.5, 1.5, nil},
+		{float32(0.25), 0.25, nil},
+		{int64(-7), -7, nil},
+		{uint16(65535), 65535, nil},
+		{register(42), 42, nil},
+		{true, 1, nil},
+		{json.Number("12.5"), 12.5, nil},
+		{" 21.5 ", 21.5, nil},
+		{"0x10", 16, nil},
+		{"1e3", 1000, nil},
+		{"", 0, ErrInvalidString},
+		{"abc", 0, ErrInvalidString},
+		{"NaN", 0, ErrNotFinite},
+		{"-Inf", 0, ErrNotFinite},
+		{"1e999", 0, ErrNotFinite},
+		{math.NaN(), 0, ErrNotFinite},
+		{math.Inf(1), 0, ErrNotFinite},
+		{float32(math.Inf(-1)), 0, ErrNotFinite},
+		{nil, 0, ErrNil},
+		{[]int{1}, 0, ErrUnsupported},
+	}
+	for _, testCase := range testCases {
+		result, err := ToFloat(testCase.value)
+		if !errors.Is(err, testCase.expectErr) {
+			t.Errorf("Value: %v, Expected err: %v, Got: %v", testCase.value, testCase.expectErr, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Value: %v, Expected: %v, Got: %v", testCase.value, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestToInt(t *testing.T) {
+	testCases := []struct {
+		value          any
+		expectedResult int
+		expectErr      error
+	}{
+		{3.0, 3, nil},
+		{3.5, 0, ErrFraction},
+		{int64(1) << 62, 1 << 62, nil},
+		{uint64(1) << 63, 0, ErrOutOfRange},
+		{1e19, 0, ErrOutOfRange},
+		{"42", 42, nil},
+		{"0b101", 5, nil},
+		{false, 0, nil},
+		{"4.2", 0, ErrFraction},
+	}
+	for _, testCase := range testCases {
+		result, err := ToInt(testCase.value)
+		if !errors.Is(err, testCase.expectErr) {
+			t.Errorf("Value: %v, Expected err: %v, Got: %v", testCase.value, testCase.expectErr, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Value: %v, Expected: %v, Got: %v", testCase.value, testCase.expectedResult, result)
+		}
+	}
+	rules := DefaultRules()
+	rules.Truncate = true
+	if v, err := rules.ToInt(-3.7); err != nil || v != -3 {
+		t.Errorf("expected -3 when truncating, got %v %v", v, err)
+	}
+}
+
+func TestToBool(t *testing.T) {
+	strict, loose := DefaultRules(), DefaultRules()
+	strict.Truth = TruthStrict
+	loose.Truth = TruthLoose
+	testCases := []struct {
+		rules          Rules
+		value          any
+		expectedResult bool
+		expectErr      error
+	}{
+		{defaultRules, true, true, nil},
+		{defaultRules, 0.0, false, nil},
+		{defaultRules, float32(0.5), true, nil},
+		{defaultRules, uint16(0), false, nil},
+		{defaultRules, " Yes ", true, nil},
+		{defaultRules, "OFF", false, nil},
+		{defaultRules, "0", false, nil},
+		{defaultRules, "2.5", true, nil},
+		{defaultRules, "maybe", false, ErrInvalidString},
+		{defaultRules, "", false, ErrInvalidString},
+		{defaultRules, nil, false, ErrNil},
+		{defaultRules, struct{}{}, false, ErrUnsupported},
+		{strict, "true", true, nil},
+		{strict, 1, false, ErrUnsupported},
+		{strict, "1", false, ErrInvalidString},
+		{loose, "maybe", true, nil},
+		{loose, map[string]any{}, true, nil},
+		{loose, 0, false, nil},
+	}
+	for _, testCase := range testCases {
+		result, err := testCase.rules.ToBool(testCase.value)
+		if !errors.Is(err, testCase.expectErr) {
+			t.Errorf("Truth: %s, Value: %v, Expected err: %v, Got: %v", testCase.rules.Truth, testCase.value, testCase.expectErr, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Truth: %s, Value: %v, Expected: %v, Got: %v", testCase.rules.Truth, testCase.value, testCase.expectedResult, result)
+		}
+	}
+	custom := DefaultRules()
+	custom.TrueStrings = []string{"ein"}
+	custom.FalseStrings = []string{"aus"}
+	if v, err := custom.ToBool("EIN"); err != nil || !v {
+		t.Errorf("expected a custom true string, got %v %v", v, err)
+	}
+	nilRules := DefaultRules()
+	nilRules.NilIsZero = true
+	nilRules.EmptyIsZero = true
+	for _, value := range []any{nil, ""} {
+		if v, err := nilRules.ToBool(value); err != nil || v {
+			t.Errorf("Value: %q, expected false, got %v %v", value, v, err)
+		}
+	}
+}
+
+func TestToString(t *testing.T) {
+	testCases := []struct {
+		value          any
+		expectedResult string
+		expectErr      error
+	}{
+		{"text", "text", nil},
+		{1.5, "1.5", nil},
+		{float32(0.1), "0.1", nil},
+		{int64(-3), "-3", nil},
+		{register(7), "7", nil},
+		{false, "false", nil},
+		{json.Number("1e3"), "1e3", nil},
+		{[]byte("raw"), "raw", nil},
+		{nil, "", ErrNil},
+		{map[string]int{}, "", ErrUnsupported},
+	}
+	for _, testCase := range testCases {
+		result, err := ToString(testCase.value)
+		if !errors.Is(err, testCase.expectErr) {
+			t.Errorf("Value: %v, Expected err: %v, Got: %v", testCase.value, testCase.expectErr, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Value: %v, Expected: %q, Got: %q", testCase.value, testCase.expectedResult, result)
+		}
+	}
+	rules := DefaultRules()
+	rules.Precision = 2
+	if v, _ := rules.ToString(1.0 / 3); v != "0.33" {
+		t.Errorf("expected 0.33, got %s", v)
+	}
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Generator produces a series of values, a generator is not safe for use by more than one goroutine
+type Generator interface {
+	Next() float64
+}
+
+// newRand returns a seeded source, a seed of 0 uses the current time so the series will not repeat
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+type uniform struct {
+	r        *rand.Rand
+	min, max float64
+}
+
+// NewUniform returns values evenly spread between min and max
+func NewUniform(seed int64, min, max float64) Generator {
+	if min > max {
+		min, max = max, min
+	}
+	return &uniform{r: newRand(seed), min: min, max: max}
+}
+
+func (g *uniform) Next() float64 {
+	return g.min + g.r.Float64()*(g.max-g.min)
+}
+
+type gaussian struct {
+	r            *rand.Rand
+	mean, stddev float64
+}
+
+// NewGaussian returns normally distributed values
+func NewGaussian(seed int64, mean, stddev float64) Generator {
+	return &gaussian{r: newRand(seed), mean: mean, stddev: math.Abs(stddev)}
+}
+
+func (g *gaussian) Next() float64 {
+	return g.mean + g.r.NormFloat64()*g.stddev
+}
+
+type randomWalk struct {
+	r        *rand.Rand
+	value    float64
+	step     float64
+	min, max float64
+}
+
+// NewRandomWalk starts at start and moves up to step either way on each call, the value is reflected back off min and max
+// this gives a slowly drifting value like a real temperature sensor
+func NewRandomWalk(seed int64, start, step, min, max float64) Generator {
+	if min > max {
+		min, max = max, min
+	}
+	return &randomWalk{r: newRand(seed), value: clamp(start, min, max), step: math.Abs(step), min: min, max: max}
+}
+
+func (g *randomWalk) Next() float64 {
+	value := g.value
+	g.value += (g.r.Float64()*2 - 1) * g.step
+	if g.value > g.max {
+		g.value = g.max - (g.value - g.max)
+	}
+	if g.value < g.min {
+		g.value = g.min + (g.min - g.value)
+	}
+	g.value = clamp(g.value, g.min, g.max)
+	return value
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func series(g Generator, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = g.Next()
+	}
+	return out
+}
+
+func TestSeeded(t *testing.T) {
+	testCases := []struct {
+		name string
+		new  func() Generator
+	}{
+		{"uniform", func() Generator { return NewUniform(42, 1, 10) }},
+		{"gaussian", func() Generator { return NewGaussian(42, 20, 2) }},
+		{"walk", func() Generator { return NewRandomWalk(42, 20, 0.5, 15, 25) }},
+	}
+	for _, testCase := range testCases {
+		a := series(testCase.new(), 50)
+		b := series(testCase.new(), 50)
+		for i := range a {
+			if a[i] != b[i] {
+				t.Errorf("%s: same seed gave different values at %d: %f != %f", testCase.name, i, a[i], b[i])
+				break
+			}
+		}
+	}
+}
+
+func TestUniformRange(t *testing.T) {
+	for _, v := range series(NewUniform(1, 1, 10), 1000) {
+		if v < 1 || v >= 10 {
+			t.Fatalf("value %f out of range", v)
+		}
+		if v != math.Trunc(v) {
+			return // not truncated to an int
+		}
+	}
+	t.Errorf("all values were whole numbers")
+}
+
+func TestRandomWalkBounds(t *testing.T) {
+	g := NewRandomWalk(7, 24.9, 2, 15, 25)
+	last := g.Next()
+	for _, v := range series(g, 1000) {
+		if v < 15 || v > 25 {
+			t.Fatalf("value %f out of bounds", v)
+		}
+		if math.Abs(v-last) > 2 {
+			t.Fatalf("step from %f to %f is larger than 2", last, v)
+		}
+		last = v
+	}
+}
+
+func TestReplay(t *testing.T) {
+	data := "time,temp\n0,20.5\n1,21\n2,bad\n3,22.25\n"
+	g, err := NewReplay(strings.NewReader(data), 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{20.5, 21, 22.25, 20.5}
+	for i, v := range series(g, 4) {
+		if v != expected[i] {
+			t.Errorf("index %d: Expected: %f, Got: %f", i, expected[i], v)
+		}
+	}
+
+	g, _ = NewReplay(strings.NewReader(data), 1, false)
+	result := series(g, 5)
+	if result[4] != 22.25 {
+		t.Errorf("expected the last value to be held, got %f", result[4])
+	}
+
+	if _, err := NewReplay(strings.NewReader("a,b\n"), 0, false); err == nil {
+		t.Errorf("expected an error for a csv with no values")
+	}
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type replay struct {
+	values []float64
+	index  int
+	loop   bool
+}
+
+// NewReplay replays a recorded series from one column of a csv, rows that can not be parsed as a number (eg; a header) are skipped
+// once the series has been played it starts again if loop is set, otherwise the last value is held
+func NewReplay(r io.Reader, column int, loop bool) (Generator, error) {
+	if column < 0 {
+		return nil, fmt.Errorf("invalid csv column: %d", column)
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var values []float64
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if column >= len(record) {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(record[column]), 64)
+		if err != nil {
+			continue
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values found in csv")
+	}
+	return &replay{values: values, loop: loop}, nil
+}
+
+// NewReplayFile replays a recorded series from a csv file, see NewReplay
+func NewReplayFile(path string, column int, loop bool) (Generator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewReplay(f, column, loop)
+}
+
+func (g *replay) Next() float64 {
+	value := g.values[g.index]
+	if g.index < len(g.values)-1 {
+		g.index++
+	} else if g.loop {
+		g.index = 0
+	}
+	return value
+}
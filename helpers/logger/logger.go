@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRecent is how many lines are kept for each object
+const DefaultRecent = 200
+
+// Default is the registry the nodes log to, it writes json lines to stdout
+var Default = NewRegistry(os.Stdout, slog.LevelInfo, DefaultRecent)
+
+// Registry holds a logger for each object, every logger writes json lines to the same output with the uuid, name and
+// type of its object, its level can be changed while the object runs
+type Registry struct {
+	mu      sync.Mutex
+	out     io.Writer
+	level   slog.Level // level of new loggers
+	recent  int
+	objects map[string]*Logger
+}
+
+func NewRegistry(out io.Writer, level slog.Level, recent int) *Registry {
+	return &Registry{
+		out:     &lockedWriter{w: out},
+		level:   level,
+		recent:  recent,
+		objects: make(map[string]*Logger),
+	}
+}
+
+// Logger logs for one object and keeps its recent lines
+type Logger struct {
+	*slog.Logger
+	UUID  string
+	Name  string
+	Type  string
+	level *slog.LevelVar
+	lines *ring
+}
+
+// Line is a log line kept for an object
+type Line struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// For returns the logger of the object, it is created on first use, the name and type are updated if they changed
+func (r *Registry) For(uuid, name, objectType string) *Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.objects[uuid]; ok && l.Name == name && l.Type == objectType {
+		return l
+	}
+	level := new(slog.LevelVar)
+	level.Set(r.level)
+	lines := newRing(r.recent)
+	if l, ok := r.objects[uuid]; ok {
+		// keep the level and lines of an object that was renamed
+		level, lines = l.level, l.lines
+	}
+	json := slog.NewJSONHandler(r.out, &slog.HandlerOptions{Level: level})
+	l := &Logger{
+		Logger: slog.New(&handler{json: json, lines: lines}).With("uuid", uuid, "name", name, "type", objectType),
+		UUID:   uuid,
+		Name:   name,
+		Type:   objectType,
+		level:  level,
+		lines:  lines,
+	}
+	r.objects[uuid] = l
+	return l
+}
+
+// Get returns the logger of the object, false if it has not logged
+func (r *Registry) Get(uuid string) (*Logger, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.objects[uuid]
+	return l, ok
+}
+
+// All returns the loggers sorted by uuid
+func (r *Registry) All() []*Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := make([]*Logger, 0, len(r.objects))
+	for _, l := range r.objects {
+		all = append(all, l)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].UUID < all[j].UUID })
+	return all
+}
+
+// Remove drops the logger and the lines of the object
+func (r *Registry) Remove(uuid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.objects, uuid)
+}
+
+// SetDefaultLevel sets the level of the loggers created from now on
+func (r *Registry) SetDefaultLevel(level slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.level = level
+}
+
+// DefaultLevel is the level of new loggers
+func (r *Registry) DefaultLevel() slog.Level {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.level
+}
+
+// SetLevel changes the level of the object
+func (l *Logger) SetLevel(level slog.Level) {
+	l.level.Set(level)
+}
+
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// Recent returns the lines kept for the object, oldest first
+func (l *Logger) Recent() []Line {
+	return l.lines.all()
+}
+
+// ParseLevel reads debug, info, warn or error
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return level, fmt.Errorf("level must be debug, info, warn or error, got %q", s)
+	}
+	return level, nil
+}
+
+// handler writes the json line and keeps the line for the object
+type handler struct {
+	json  slog.Handler
+	lines *ring
+	attrs []slog.Attr
+	group string
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.json.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	line := Line{Time: record.Time, Level: record.Level.String(), Message: record.Message}
+	add := func(a slog.Attr) bool {
+		switch a.Key {
+		case "uuid", "name", "type":
+			// the object is known from the logger the lines are read from
+			return true
+		}
+		if line.Attrs == nil {
+			line.Attrs = make(map[string]any)
+		}
+		key := a.Key
+		if h.group != "" {
+			key = h.group + "." + key
+		}
+		line.Attrs[key] = a.Value.Resolve().Any()
+		return true
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	record.Attrs(add)
+	h.lines.add(line)
+	return h.json.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{json: h.json.WithAttrs(attrs), lines: h.lines, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...), group: h.group}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	group := name
+	if h.group != "" {
+		group = h.group + "." + name
+	}
+	return &handler{json: h.json.WithGroup(name), lines: h.lines, attrs: h.attrs, group: group}
+}
+
+// ring keeps the last lines
+type ring struct {
+	mu    sync.Mutex
+	lines []Line
+	next  int
+	full  bool
+}
+
+func newRing(size int) *ring {
+	return &ring{lines: make([]Line, max(size, 1))}
+}
+
+func (r *ring) add(line Line) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring) all() []Line {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Line(nil), r.lines[:r.next]...)
+	}
+	return append(append([]Line(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// lockedWriter stops lines from different objects interleaving
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	r := NewRegistry(&out, slog.LevelInfo, 2)
+	l := r.For("uuid-1", "boiler", "trigger")
+	l.Debug("hidden")
+	l.Info("first", "value", 1)
+	l.With("port", "output").Warn("second")
+	l.Error("third")
+
+	var line map[string]any
+	first, _, _ := strings.Cut(out.String(), "\n")
+	if err := json.Unmarshal([]byte(first), &line); err != nil {
+		t.Fatalf("expected a json line, got %q: %v", first, err)
+	}
+	if line["uuid"] != "uuid-1" || line["name"] != "boiler" || line["type"] != "trigger" || line["msg"] != "first" {
+		t.Errorf("unexpected line: %v", line)
+	}
+
+	// only the last two lines are kept
+	recent := l.Recent()
+	if len(recent) != 2 || recent[0].Message != "second" || recent[1].Message != "third" {
+		t.Fatalf("unexpected lines: %+v", recent)
+	}
+	if recent[0].Attrs["port"] != "output" || recent[0].Level != "WARN" {
+		t.Errorf("unexpected line: %+v", recent[0])
+	}
+
+	l.SetLevel(slog.LevelDebug)
+	l.Debug("shown")
+	if recent := l.Recent(); recent[1].Message != "shown" {
+		t.Errorf("Expected: shown, Got: %s", recent[1].Message)
+	}
+	if again := r.For("uuid-1", "boiler", "trigger"); again != l {
+		t.Error("expected the same logger for the object")
+	}
+	if renamed := r.For("uuid-1", "pump", "trigger"); renamed.Level() != slog.LevelDebug || len(renamed.Recent()) != 2 {
+		t.Error("expected a renamed object to keep its level and lines")
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewRegistry(&bytes.Buffer{}, slog.LevelInfo, DefaultRecent)
+	r.For("uuid-1", "boiler", "trigger").Info("hello")
+	router := gin.New()
+	r.Routes(router)
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	tests := []struct {
+		method, path, body string
+		status             int
+		contains           string
+	}{
+		{http.MethodGet, "/logs", "", http.StatusOK, `"uuid":"uuid-1"`},
+		{http.MethodGet, "/logs/uuid-1", "", http.StatusOK, `"msg":"hello"`},
+		{http.MethodGet, "/logs/uuid-2", "", http.StatusNotFound, "no logs"},
+		{http.MethodPut, "/logs/uuid-1/level", `{"level": "debug"}`, http.StatusOK, `"level":"DEBUG"`},
+		{http.MethodPut, "/logs/uuid-1/level", `{"level": "loud"}`, http.StatusBadRequest, "level must be"},
+		{http.MethodPut, "/logs/level", `{"level": "warn"}`, http.StatusOK, `"level":"WARN"`},
+	}
+	for _, test := range tests {
+		w := request(test.method, test.path, test.body)
+		if w.Code != test.status || !strings.Contains(w.Body.String(), test.contains) {
+			t.Errorf("%s %s Expected: %d %s, Got: %d %s", test.method, test.path, test.status, test.contains, w.Code, w.Body)
+		}
+	}
+	if l, _ := r.Get("uuid-1"); l.Level() != slog.LevelDebug {
+		t.Errorf("Expected: DEBUG, Got: %s", l.Level())
+	}
+	if r.DefaultLevel() != slog.LevelWarn {
+		t.Errorf("Expected: WARN, Got: %s", r.DefaultLevel())
+	}
+}
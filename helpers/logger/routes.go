@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// Object is the logger of an object as returned by the routes
+type Object struct {
+	UUID  string `json:"uuid"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Level string `json:"level"`
+	Lines []Line `json:"lines,omitempty"`
+}
+
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+func (l *Logger) object(lines bool) *Object {
+	o := &Object{UUID: l.UUID, Name: l.Name, Type: l.Type, Level: l.Level().String()}
+	if lines {
+		o.Lines = l.Recent()
+	}
+	return o
+}
+
+// Routes adds the log routes to the router
+//
+//	GET /logs              every object that has logged and its level
+//	GET /logs/:uuid        the recent lines of an object
+//	PUT /logs/:uuid/level  {"level": "debug"} changes the level of an object
+//	PUT /logs/level        {"level": "debug"} changes the level of objects that have not logged yet
+func (r *Registry) Routes(router gin.IRouter) {
+	logs := router.Group("/logs")
+	logs.GET("", func(c *gin.Context) {
+		var objects []*Object
+		for _, l := range r.All() {
+			objects = append(objects, l.object(false))
+		}
+		c.JSON(http.StatusOK, objects)
+	})
+	logs.GET("/:uuid", func(c *gin.Context) {
+		l, ok := r.Get(c.Param("uuid"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no logs for " + c.Param("uuid")})
+			return
+		}
+		c.JSON(http.StatusOK, l.object(true))
+	})
+	logs.PUT("/:uuid/level", func(c *gin.Context) {
+		l, ok := r.Get(c.Param("uuid"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no logs for " + c.Param("uuid")})
+			return
+		}
+		var body levelBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		level, err := ParseLevel(body.Level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		l.SetLevel(level)
+		c.JSON(http.StatusOK, l.object(false))
+	})
+	logs.PUT("/level", func(c *gin.Context) {
+		var body levelBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		level, err := ParseLevel(body.Level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		r.SetDefaultLevel(level)
+		c.JSON(http.StatusOK, levelBody{Level: level.String()})
+	})
+}
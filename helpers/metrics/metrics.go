@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default is the registry the nodes record to
+var Default = NewRegistry("reactive_nodes")
+
+// Registry holds the metrics of each object, they are written in the prometheus text format with the uuid, name and
+// type of the object as labels
+type Registry struct {
+	prefix string
+
+	mu      sync.Mutex
+	objects map[string]*Object
+	help    map[string]string // driver metric -> help
+}
+
+func NewRegistry(prefix string) *Registry {
+	return &Registry{
+		prefix:  prefix,
+		objects: make(map[string]*Object),
+		help:    make(map[string]string),
+	}
+}
+
+// Object is the metrics of one object
+type Object struct {
+	UUID string
+	Name string
+	Type string
+
+	mu            sync.Mutex
+	in            map[string]uint64 // port -> messages
+	out           map[string]uint64 // port -> messages
+	publishErrors uint64
+	processing    summary
+	lastActivity  time.Time
+	counters      map[string]float64
+	summaries     map[string]*summary
+}
+
+// summary is the count and sum of observations, eg; seconds taken to handle each message
+type summary struct {
+	count uint64
+	sum   float64
+}
+
+func (s *summary) observe(v float64) {
+	s.count++
+	s.sum += v
+}
+
+// For returns the metrics of the object, they are created on first use, the name and type are updated if they changed
+func (r *Registry) For(uuid, name, objectType string) *Object {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.objects[uuid]
+	if !ok {
+		o = &Object{
+			UUID:      uuid,
+			in:        make(map[string]uint64),
+			out:       make(map[string]uint64),
+			counters:  make(map[string]float64),
+			summaries: make(map[string]*summary),
+		}
+		r.objects[uuid] = o
+	}
+	o.mu.Lock()
+	o.Name, o.Type = name, objectType
+	o.mu.Unlock()
+	return o
+}
+
+// Get returns the metrics of the object, false if nothing has been recorded
+func (r *Registry) Get(uuid string) (*Object, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.objects[uuid]
+	return o, ok
+}
+
+// Remove drops the metrics of the object
+func (r *Registry) Remove(uuid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.objects, uuid)
+}
+
+// Describe sets the help text of a driver counter or summary, eg; modbus_polls_total
+func (r *Registry) Describe(name, help string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.help[name] = help
+}
+
+func (r *Registry) all() []*Object {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := make([]*Object, 0, len(r.objects))
+	for _, o := range r.objects {
+		all = append(all, o)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].UUID < all[j].UUID })
+	return all
+}
+
+// In counts a message read from the input
+func (o *Object) In(portID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.in[portID]++
+	o.lastActivity = time.Now()
+}
+
+// Out counts a message published on the output
+func (o *Object) Out(portID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.out[portID]++
+	o.lastActivity = time.Now()
+}
+
+// PublishError counts a value that could not be published
+func (o *Object) PublishError() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.publishErrors++
+}
+
+// Processed records the time taken to handle a message
+func (o *Object) Processed(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.processing.observe(d.Seconds())
+}
+
+// Add adds to a driver counter, the name should end in _total
+func (o *Object) Add(name string, delta float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.counters[name] += delta
+	o.lastActivity = time.Now()
+}
+
+// Observe adds a value to a driver summary, eg; modbus_poll_seconds
+func (o *Object) Observe(name string, value float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	s, ok := o.summaries[name]
+	if !ok {
+		s = &summary{}
+		o.summaries[name] = s
+	}
+	s.observe(value)
+}
+
+// Snapshot is a copy of the metrics of an object
+type Snapshot struct {
+	In            map[string]uint64
+	Out           map[string]uint64
+	PublishErrors uint64
+	Processed     uint64
+	LastActivity  time.Time
+	Counters      map[string]float64
+}
+
+func (o *Object) Snapshot() Snapshot {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	s := Snapshot{
+		In:            make(map[string]uint64, len(o.in)),
+		Out:           make(map[string]uint64, len(o.out)),
+		PublishErrors: o.publishErrors,
+		Processed:     o.processing.count,
+		LastActivity:  o.lastActivity,
+		Counters:      make(map[string]float64, len(o.counters)),
+	}
+	for k, v := range o.in {
+		s.In[k] = v
+	}
+	for k, v := range o.out {
+		s.Out[k] = v
+	}
+	for k, v := range o.counters {
+		s.Counters[k] = v
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry("test")
+	r.Describe("modbus_polls_total", "Polls.")
+	b := r.For("uuid-b", `pump "2"`, "add")
+	b.In("input-1")
+	b.In("input-1")
+	b.Out("output")
+	b.Processed(250 * time.Millisecond)
+	b.Processed(250 * time.Millisecond)
+	a := r.For("uuid-a", "boiler", "modbus-network")
+	a.PublishError()
+	a.Add("modbus_polls_total", 3)
+	a.Observe("modbus_poll_seconds", 0.5)
+
+	var out bytes.Buffer
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	expected := []string{
+		"# TYPE test_messages_in_total counter",
+		`test_messages_in_total{uuid="uuid-b",name="pump \"2\"",type="add",port="input-1"} 2`,
+		`test_messages_out_total{uuid="uuid-b",name="pump \"2\"",type="add",port="output"} 1`,
+		`test_publish_errors_total{uuid="uuid-a",name="boiler",type="modbus-network"} 1`,
+		`test_processing_seconds_sum{uuid="uuid-b",name="pump \"2\"",type="add"} 0.5`,
+		`test_processing_seconds_count{uuid="uuid-b",name="pump \"2\"",type="add"} 2`,
+		"# HELP test_modbus_polls_total Polls.",
+		`test_modbus_polls_total{uuid="uuid-a",name="boiler",type="modbus-network"} 3`,
+		"# TYPE test_modbus_poll_seconds summary",
+		`test_modbus_poll_seconds_count{uuid="uuid-a",name="boiler",type="modbus-network"} 1`,
+		`test_last_activity_timestamp_seconds{uuid="uuid-b"`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected: %s, Got:\n%s", line, text)
+		}
+	}
+	// objects are written in uuid order
+	if strings.Index(text, `test_publish_errors_total{uuid="uuid-a"`) > strings.Index(text, `test_publish_errors_total{uuid="uuid-b"`) {
+		t.Errorf("expected uuid-a before uuid-b:\n%s", text)
+	}
+	if strings.Count(text, "# TYPE test_publish_errors_total") != 1 {
+		t.Errorf("expected one TYPE line for each metric:\n%s", text)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewRegistry("test")
+	r.For("uuid-1", "boiler", "trigger").Out("output")
+	router := gin.New()
+	r.Routes(router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Expected: 200 %s, Got: %d %s", ContentType, w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), `test_messages_out_total{uuid="uuid-1",name="boiler",type="trigger",port="output"} 1`) {
+		t.Errorf("unexpected body: %s", w.Body)
+	}
+}
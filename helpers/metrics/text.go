@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	counterType = "counter"
+	gaugeType   = "gauge"
+	summaryType = "summary"
+)
+
+// family is a metric and its series, each line is the labels and value after the name
+type family struct {
+	name   string
+	kind   string
+	help   string
+	series []string
+}
+
+func (f *family) add(suffix string, labels string, value float64) {
+	f.series = append(f.series, f.name+suffix+labels+" "+strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// WriteText writes every metric in the prometheus text format, objects are in uuid order
+func (r *Registry) WriteText(w io.Writer) error {
+	in := &family{name: r.prefix + "_messages_in_total", kind: counterType, help: "Messages read from each input of an object."}
+	out := &family{name: r.prefix + "_messages_out_total", kind: counterType, help: "Messages published on each output of an object."}
+	publishErrors := &family{name: r.prefix + "_publish_errors_total", kind: counterType, help: "Values an object could not publish."}
+	processing := &family{name: r.prefix + "_processing_seconds", kind: summaryType, help: "Time taken by an object to handle a message."}
+	lastActivity := &family{name: r.prefix + "_last_activity_timestamp_seconds", kind: gaugeType, help: "Unix time an object last read or published a message."}
+	r.mu.Lock()
+	help := make(map[string]string, len(r.help))
+	for name, text := range r.help {
+		help[name] = text
+	}
+	r.mu.Unlock()
+	drivers := make(map[string]*family)
+	driver := func(name, kind string) *family {
+		f, ok := drivers[name]
+		if !ok {
+			f = &family{name: r.prefix + "_" + name, kind: kind, help: help[name]}
+			drivers[name] = f
+		}
+		return f
+	}
+
+	for _, o := range r.all() {
+		o.mu.Lock()
+		object := []string{"uuid", o.UUID, "name", o.Name, "type", o.Type}
+		for _, port := range sortedKeys(o.in) {
+			in.add("", labels(append(object, "port", port)...), float64(o.in[port]))
+		}
+		for _, port := range sortedKeys(o.out) {
+			out.add("", labels(append(object, "port", port)...), float64(o.out[port]))
+		}
+		publishErrors.add("", labels(object...), float64(o.publishErrors))
+		if o.processing.count > 0 {
+			processing.add("_sum", labels(object...), o.processing.sum)
+			processing.add("_count", labels(object...), float64(o.processing.count))
+		}
+		if !o.lastActivity.IsZero() {
+			lastActivity.add("", labels(object...), float64(o.lastActivity.UnixMilli())/1000)
+		}
+		for _, name := range sortedKeys(o.counters) {
+			driver(name, counterType).add("", labels(object...), o.counters[name])
+		}
+		for _, name := range sortedKeys(o.summaries) {
+			s := o.summaries[name]
+			f := driver(name, summaryType)
+			f.add("_sum", labels(object...), s.sum)
+			f.add("_count", labels(object...), float64(s.count))
+		}
+		o.mu.Unlock()
+	}
+
+	families := []*family{in, out, publishErrors, processing, lastActivity}
+	for _, name := range sortedKeys(drivers) {
+		families = append(families, drivers[name])
+	}
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.series) == 0 {
+			continue
+		}
+		if f.help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+		for _, line := range f.series {
+			bw.WriteString(line)
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// Routes adds GET /metrics to the router for prometheus to scrape
+func (r *Registry) Routes(router gin.IRouter) {
+	router.GET("/metrics", func(c *gin.Context) {
+		c.Header("Content-Type", ContentType)
+		c.Status(http.StatusOK)
+		_ = r.WriteText(c.Writer)
+	})
+}
+
+// labels formats name, value pairs as {name="value",...}
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+package modbussim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Config describes a simulator as json, it is used by the command so a demo can be set up without code
+type Config struct {
+	Framing Framing        `json:"framing"` // tcp or rtu
+	Address string         `json:"address"` // listen address, eg; 0.0.0.0:10502
+	Devices []DeviceConfig `json:"devices"`
+}
+
+type DeviceConfig struct {
+	Address    byte              `json:"address"` // slave id
+	Latency    int               `json:"latency"` // ms added to each response
+	Timeout    bool              `json:"timeout"` // do not respond
+	Banks      []BankConfig      `json:"banks"`
+	Scripts    []ScriptConfig    `json:"scripts"`
+	Exceptions []ExceptionConfig `json:"exceptions"`
+}
+
+type BankConfig struct {
+	Bank   Bank     `json:"bank"`
+	Start  uint16   `json:"start"`
+	Count  int      `json:"count"`
+	Values []uint16 `json:"values"` // set from the start of the bank
+}
+
+type ScriptConfig struct {
+	Bank    Bank         `json:"bank"`
+	Address uint16       `json:"address"`
+	Loop    bool         `json:"loop"`
+	Steps   []StepConfig `json:"steps"`
+}
+
+type StepConfig struct {
+	Value uint16 `json:"value"`
+	Hold  int    `json:"hold"` // ms
+}
+
+type ExceptionConfig struct {
+	Function byte `json:"function"` // 0 for every function
+	Code     byte `json:"code"`
+}
+
+// DefaultConfig is a tcp device at address 1 on the port the modbus network uses by default, with 100 of each bank and
+// holding register 0 ramping from 0 to 9 once a second
+func DefaultConfig() Config {
+	device := DeviceConfig{Address: 1}
+	for _, b := range Banks {
+		device.Banks = append(device.Banks, BankConfig{Bank: b, Count: 100})
+	}
+	ramp := ScriptConfig{Bank: HoldingRegisters, Loop: true}
+	for i := uint16(0); i < 10; i++ {
+		ramp.Steps = append(ramp.Steps, StepConfig{Value: i, Hold: 1000})
+	}
+	device.Scripts = append(device.Scripts, ramp)
+	return Config{Framing: TCP, Address: "0.0.0.0:10502", Devices: []DeviceConfig{device}}
+}
+
+// LoadConfig reads a json config file
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Server builds the server from the config, it is not listening yet
+func (c Config) Server() (*Server, error) {
+	framing := c.Framing
+	if framing == "" {
+		framing = TCP
+	}
+	if framing != TCP && framing != RTU {
+		return nil, fmt.Errorf("unknown framing %s", framing)
+	}
+	s := NewServer(framing)
+	for _, dc := range c.Devices {
+		if dc.Address == 0 || dc.Address > 247 {
+			return nil, fmt.Errorf("device address must be 1 to 247, got %d", dc.Address)
+		}
+		d := s.AddDevice(dc.Address)
+		d.SetLatency(time.Duration(dc.Latency) * time.Millisecond).SetTimeout(dc.Timeout)
+		for _, bc := range dc.Banks {
+			if !slices.Contains(Banks, bc.Bank) {
+				return nil, fmt.Errorf("device %d: unknown bank %s", dc.Address, bc.Bank)
+			}
+			d.AddBank(bc.Bank, bc.Start, max(bc.Count, len(bc.Values)))
+			if len(bc.Values) > 0 {
+				if err := d.Set(bc.Bank, bc.Start, bc.Values...); err != nil {
+					return nil, fmt.Errorf("device %d: %w", dc.Address, err)
+				}
+			}
+		}
+		for _, sc := range dc.Scripts {
+			var steps []Step
+			for _, step := range sc.Steps {
+				steps = append(steps, Step{Value: step.Value, Hold: time.Duration(step.Hold) * time.Millisecond})
+			}
+			if err := d.Script(sc.Bank, sc.Address, sc.Loop, steps...); err != nil {
+				return nil, fmt.Errorf("device %d: %w", dc.Address, err)
+			}
+		}
+		for _, ec := range dc.Exceptions {
+			d.SetException(ec.Function, ec.Code)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,231 @@
+package modbussim
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Bank is a register bank of a device
+type Bank string
+
+const (
+	Coils            Bank = "coils"
+	DiscreteInputs   Bank = "discrete-inputs"
+	HoldingRegisters Bank = "holding-registers"
+	InputRegisters   Bank = "input-registers"
+)
+
+// Banks are the banks in the order of their read function codes
+var Banks = []Bank{Coils, DiscreteInputs, HoldingRegisters, InputRegisters}
+
+// bank is a range of addresses, a coil or discrete input is stored as 0 or 1
+type bank struct {
+	start  uint16
+	values []uint16
+}
+
+func (b *bank) contains(address uint16, count int) bool {
+	return b != nil && address >= b.start && int(address-b.start)+count <= len(b.values)
+}
+
+// Step is a value a script holds for a time before moving to the next step
+type Step struct {
+	Value uint16
+	Hold  time.Duration
+}
+
+// script sets an address from its steps based on the time since it was added, the last value is kept once the steps
+// run out unless the script loops
+type script struct {
+	bank    Bank
+	address uint16
+	steps   []Step
+	loop    bool
+	start   time.Time
+}
+
+func (s *script) value(now time.Time) uint16 {
+	elapsed := now.Sub(s.start)
+	var total time.Duration
+	for _, step := range s.steps {
+		total += step.Hold
+	}
+	if s.loop && total > 0 {
+		elapsed %= total
+	}
+	for _, step := range s.steps {
+		if elapsed < step.Hold {
+			return step.Value
+		}
+		elapsed -= step.Hold
+	}
+	return s.steps[len(s.steps)-1].Value
+}
+
+// Device is a simulated slave, it answers requests from its banks and can be set to respond slowly, not at all or with
+// an exception
+type Device struct {
+	mu         sync.Mutex
+	address    byte
+	now        func() time.Time
+	banks      map[Bank]*bank
+	scripts    []*script
+	latency    time.Duration
+	timeout    bool
+	exceptions map[byte]byte // function code, 0 for every function -> exception code
+}
+
+func newDevice(address byte, now func() time.Time) *Device {
+	return &Device{
+		address:    address,
+		now:        now,
+		banks:      make(map[Bank]*bank),
+		exceptions: make(map[byte]byte),
+	}
+}
+
+// Address is the slave id of the device
+func (d *Device) Address() byte {
+	return d.address
+}
+
+// AddBank adds count addresses to the bank from start, all set to 0, it replaces the bank if it was already added
+// a request outside the banks gets an illegal data address exception
+func (d *Device) AddBank(b Bank, start uint16, count int) *Device {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.banks[b] = &bank{start: start, values: make([]uint16, count)}
+	return d
+}
+
+// Set sets the values of the bank from the address
+func (d *Device) Set(b Bank, address uint16, values ...uint16) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.set(b, address, values)
+}
+
+func (d *Device) set(b Bank, address uint16, values []uint16) error {
+	bk := d.banks[b]
+	if !bk.contains(address, len(values)) {
+		return fmt.Errorf("%s %d to %d is outside the bank", b, address, int(address)+len(values)-1)
+	}
+	for i, value := range values {
+		if b == Coils || b == DiscreteInputs {
+			value = min(value, 1)
+		}
+		bk.values[int(address-bk.start)+i] = value
+	}
+	return nil
+}
+
+// Get returns the value at the address, including the value of any script on it
+func (d *Device) Get(b Bank, address uint16) (uint16, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.runScripts()
+	values, err := d.get(b, address, 1)
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+func (d *Device) get(b Bank, address uint16, count int) ([]uint16, error) {
+	bk := d.banks[b]
+	if !bk.contains(address, count) {
+		return nil, fmt.Errorf("%s %d to %d is outside the bank", b, address, int(address)+count-1)
+	}
+	start := int(address - bk.start)
+	return append([]uint16(nil), bk.values[start:start+count]...), nil
+}
+
+// Script changes the value at the address over time, the steps start from now on the clock of the server, a script
+// replaces any script already on the address and a write from a client is overwritten on the next request
+func (d *Device) Script(b Bank, address uint16, loop bool, steps ...Step) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(steps) == 0 {
+		return fmt.Errorf("a script needs at least one step")
+	}
+	if !d.banks[b].contains(address, 1) {
+		return fmt.Errorf("%s %d is outside the bank", b, address)
+	}
+	s := &script{bank: b, address: address, steps: steps, loop: loop, start: d.now()}
+	for i, existing := range d.scripts {
+		if existing.bank == b && existing.address == address {
+			d.scripts[i] = s
+			return nil
+		}
+	}
+	d.scripts = append(d.scripts, s)
+	return nil
+}
+
+// runScripts sets the current value of each script, it is called before every request so the fake clock of a test
+// decides the values
+func (d *Device) runScripts() {
+	now := d.now()
+	for _, s := range d.scripts {
+		_ = d.set(s.bank, s.address, []uint16{s.value(now)})
+	}
+}
+
+// SetLatency delays every response by latency
+func (d *Device) SetLatency(latency time.Duration) *Device {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.latency = latency
+	return d
+}
+
+// SetTimeout stops the device responding so the client times out
+func (d *Device) SetTimeout(timeout bool) *Device {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.timeout = timeout
+	return d
+}
+
+// SetException answers every request for the function code with the exception code, function 0 is every function
+// and code 0 clears the exception
+func (d *Device) SetException(function, code byte) *Device {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if code == 0 {
+		delete(d.exceptions, function)
+		return d
+	}
+	d.exceptions[function] = code
+	return d
+}
+
+// handle answers the request pdu, respond is false when the device is set to time out
+func (d *Device) handle(pdu []byte) (response []byte, respond bool) {
+	d.mu.Lock()
+	latency, timeout := d.latency, d.timeout
+	if !timeout {
+		response = d.respond(pdu)
+	}
+	d.mu.Unlock()
+	if timeout {
+		return nil, false
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+	return response, true
+}
+
+func (d *Device) respond(pdu []byte) []byte {
+	function := pdu[0]
+	if code, ok := d.exceptions[function]; ok {
+		return exception(function, code)
+	}
+	if code, ok := d.exceptions[0]; ok {
+		return exception(function, code)
+	}
+	d.runScripts()
+	return d.execute(function, pdu[1:])
+}
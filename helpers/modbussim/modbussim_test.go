@@ -0,0 +1,198 @@
+package modbussim
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"github.com/grid-x/modbus"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, framing Framing) (*Server, *Device) {
+	t.Helper()
+	s := NewServer(framing)
+	d := s.AddDevice(1)
+	for _, b := range Banks {
+		d.AddBank(b, 0, 10)
+	}
+	if err := s.Listen("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s, d
+}
+
+func newTestClient(t *testing.T, s *Server, framing Framing) (modbus.Client, modbusHandler) {
+	t.Helper()
+	var handler modbusHandler
+	if framing == RTU {
+		h := modbus.NewRTUOverTCPClientHandler(s.Addr().String())
+		h.Timeout = 200 * time.Millisecond
+		h.SlaveID = 1
+		handler = h
+	} else {
+		h := modbus.NewTCPClientHandler(s.Addr().String())
+		h.Timeout = 200 * time.Millisecond
+		h.SlaveID = 1
+		handler = h
+	}
+	t.Cleanup(func() { handler.Close() })
+	return modbus.NewClient(handler), handler
+}
+
+type modbusHandler interface {
+	modbus.ClientHandler
+	SetSlave(slaveID byte)
+	Close() error
+}
+
+func exceptionCode(err error) byte {
+	var mbErr *modbus.Error
+	if errors.As(err, &mbErr) {
+		return mbErr.ExceptionCode
+	}
+	return 0
+}
+
+func TestReadWrite(t *testing.T) {
+	for _, framing := range []Framing{TCP, RTU} {
+		s, d := newTestServer(t, framing)
+		client, _ := newTestClient(t, s, framing)
+		if err := d.Set(HoldingRegisters, 2, 100, 200); err != nil {
+			t.Fatal(err)
+		}
+		data, err := client.ReadHoldingRegisters(2, 2)
+		if err != nil {
+			t.Fatalf("%s: %v", framing, err)
+		}
+		if got := []uint16{binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])}; got[0] != 100 || got[1] != 200 {
+			t.Errorf("%s: Expected: [100 200], Got: %v", framing, got)
+		}
+		if _, err := client.WriteMultipleRegisters(5, 2, []byte{0, 7, 0, 8}); err != nil {
+			t.Fatalf("%s: %v", framing, err)
+		}
+		if _, err := client.WriteSingleCoil(3, 0xFF00); err != nil {
+			t.Fatalf("%s: %v", framing, err)
+		}
+		if _, err := client.WriteMultipleCoils(0, 2, []byte{0b10}); err != nil {
+			t.Fatalf("%s: %v", framing, err)
+		}
+		tests := []struct {
+			bank     Bank
+			address  uint16
+			expected uint16
+		}{
+			{HoldingRegisters, 5, 7},
+			{HoldingRegisters, 6, 8},
+			{Coils, 0, 0},
+			{Coils, 1, 1},
+			{Coils, 3, 1},
+		}
+		for _, test := range tests {
+			if got, _ := d.Get(test.bank, test.address); got != test.expected {
+				t.Errorf("%s: %s %d Expected: %d, Got: %d", framing, test.bank, test.address, test.expected, got)
+			}
+		}
+		coils, err := client.ReadCoils(0, 4)
+		if err != nil || len(coils) != 1 || coils[0] != 0b1010 {
+			t.Errorf("%s: Expected: [1010], Got: %b %v", framing, coils, err)
+		}
+	}
+}
+
+func TestExceptions(t *testing.T) {
+	s, d := newTestServer(t, TCP)
+	client, handler := newTestClient(t, s, TCP)
+	if _, err := client.ReadInputRegisters(9, 2); exceptionCode(err) != ExceptionIllegalDataAddress {
+		t.Errorf("Expected: illegal data address, Got: %v", err)
+	}
+	if _, err := client.ReadFIFOQueue(0); exceptionCode(err) != ExceptionIllegalFunction {
+		t.Errorf("Expected: illegal function, Got: %v", err)
+	}
+	d.SetException(readHoldingRegisters, ExceptionDeviceBusy)
+	if _, err := client.ReadHoldingRegisters(0, 1); exceptionCode(err) != ExceptionDeviceBusy {
+		t.Errorf("Expected: device busy, Got: %v", err)
+	}
+	if _, err := client.ReadInputRegisters(0, 1); err != nil {
+		t.Errorf("expected only holding registers to fail, got %v", err)
+	}
+	d.SetException(readHoldingRegisters, 0)
+	if _, err := client.ReadHoldingRegisters(0, 1); err != nil {
+		t.Errorf("expected the exception to be cleared, got %v", err)
+	}
+	handler.SetSlave(2)
+	if _, err := client.ReadHoldingRegisters(0, 1); exceptionCode(err) != ExceptionGatewayNoResponse {
+		t.Errorf("Expected: gateway no response for a missing unit, Got: %v", err)
+	}
+}
+
+func TestLatencyAndTimeout(t *testing.T) {
+	for _, framing := range []Framing{TCP, RTU} {
+		s, d := newTestServer(t, framing)
+		client, _ := newTestClient(t, s, framing)
+		d.SetLatency(50 * time.Millisecond)
+		start := time.Now()
+		if _, err := client.ReadHoldingRegisters(0, 1); err != nil {
+			t.Fatalf("%s: %v", framing, err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("%s: expected the response to take at least 50ms, took %v", framing, elapsed)
+		}
+		d.SetLatency(0).SetTimeout(true)
+		if _, err := client.ReadHoldingRegisters(0, 1); err == nil {
+			t.Errorf("%s: expected a timeout", framing)
+		}
+	}
+}
+
+func TestScript(t *testing.T) {
+	s := NewServer(TCP)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.SetClock(fake)
+	d := s.AddDevice(1).AddBank(InputRegisters, 0, 1)
+	steps := []Step{{Value: 1, Hold: time.Second}, {Value: 2, Hold: 2 * time.Second}}
+	if err := d.Script(InputRegisters, 0, true, steps...); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Script(InputRegisters, 5, true, steps...); err == nil {
+		t.Error("expected an error for a script outside the bank")
+	}
+	tests := []struct {
+		advance  time.Duration
+		expected uint16
+	}{
+		{0, 1},
+		{time.Second, 2},
+		{time.Second, 2},
+		{time.Second, 1}, // looped
+		{1500 * time.Millisecond, 2},
+	}
+	for i, test := range tests {
+		fake.Advance(test.advance)
+		if got, _ := d.Get(InputRegisters, 0); got != test.expected {
+			t.Errorf("step %d Expected: %d, Got: %d", i, test.expected, got)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	s, err := DefaultConfig().Server()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := s.Device(1); d == nil {
+		t.Fatal("expected the default device")
+	}
+	bad := []Config{
+		{Framing: "udp"},
+		{Devices: []DeviceConfig{{Address: 0}}},
+		{Devices: []DeviceConfig{{Address: 1, Banks: []BankConfig{{Bank: "registers", Count: 1}}}}},
+		{Devices: []DeviceConfig{{Address: 1, Scripts: []ScriptConfig{{Bank: Coils, Steps: []StepConfig{{Value: 1}}}}}}},
+	}
+	for i, c := range bad {
+		if _, err := c.Server(); err == nil {
+			t.Errorf("config %d: expected an error", i)
+		}
+	}
+}
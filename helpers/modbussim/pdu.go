@@ -0,0 +1,140 @@
+package modbussim
+
+import (
+	"encoding/binary"
+)
+
+const (
+	readCoils              = 1
+	readDiscreteInputs     = 2
+	readHoldingRegisters   = 3
+	readInputRegisters     = 4
+	writeSingleCoil        = 5
+	writeSingleRegister    = 6
+	writeMultipleCoils     = 15
+	writeMultipleRegisters = 16
+)
+
+// exception codes used by the simulator, any other code can be set with SetException
+const (
+	ExceptionIllegalFunction    byte = 1
+	ExceptionIllegalDataAddress byte = 2
+	ExceptionIllegalDataValue   byte = 3
+	ExceptionDeviceFailure      byte = 4
+	ExceptionDeviceBusy         byte = 6
+	ExceptionGatewayNoResponse  byte = 11
+)
+
+func exception(function, code byte) []byte {
+	return []byte{function | 0x80, code}
+}
+
+// execute runs the function on the banks and returns the response pdu
+func (d *Device) execute(function byte, data []byte) []byte {
+	switch function {
+	case readCoils, readDiscreteInputs, readHoldingRegisters, readInputRegisters:
+		if len(data) != 4 {
+			return exception(function, ExceptionIllegalDataValue)
+		}
+		bits := function == readCoils || function == readDiscreteInputs
+		address, count := binary.BigEndian.Uint16(data), int(binary.BigEndian.Uint16(data[2:]))
+		if count < 1 || (bits && count > 2000) || (!bits && count > 125) {
+			return exception(function, ExceptionIllegalDataValue)
+		}
+		values, err := d.get(Banks[function-1], address, count)
+		if err != nil {
+			return exception(function, ExceptionIllegalDataAddress)
+		}
+		if bits {
+			packed := packBits(values)
+			return append([]byte{function, byte(len(packed))}, packed...)
+		}
+		response := []byte{function, byte(count * 2)}
+		for _, value := range values {
+			response = binary.BigEndian.AppendUint16(response, value)
+		}
+		return response
+	case writeSingleCoil, writeSingleRegister:
+		if len(data) != 4 {
+			return exception(function, ExceptionIllegalDataValue)
+		}
+		address, value := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		b := HoldingRegisters
+		if function == writeSingleCoil {
+			b = Coils
+			switch value {
+			case 0xFF00:
+				value = 1
+			case 0x0000:
+			default:
+				return exception(function, ExceptionIllegalDataValue)
+			}
+		}
+		if err := d.set(b, address, []uint16{value}); err != nil {
+			return exception(function, ExceptionIllegalDataAddress)
+		}
+		return append([]byte{function}, data...)
+	case writeMultipleCoils, writeMultipleRegisters:
+		if len(data) < 5 || int(data[4]) != len(data)-5 {
+			return exception(function, ExceptionIllegalDataValue)
+		}
+		address, count := binary.BigEndian.Uint16(data), int(binary.BigEndian.Uint16(data[2:]))
+		var values []uint16
+		b := HoldingRegisters
+		if function == writeMultipleCoils {
+			b = Coils
+			if count < 1 || count > 1968 || len(data)-5 != (count+7)/8 {
+				return exception(function, ExceptionIllegalDataValue)
+			}
+			values = unpackBits(data[5:], count)
+		} else {
+			if count < 1 || count > 123 || len(data)-5 != count*2 {
+				return exception(function, ExceptionIllegalDataValue)
+			}
+			for i := 0; i < count; i++ {
+				values = append(values, binary.BigEndian.Uint16(data[5+i*2:]))
+			}
+		}
+		if err := d.set(b, address, values); err != nil {
+			return exception(function, ExceptionIllegalDataAddress)
+		}
+		return append([]byte{function}, data[:4]...)
+	default:
+		return exception(function, ExceptionIllegalFunction)
+	}
+}
+
+// packBits packs the values into bytes, the first value is the lowest bit of the first byte
+func packBits(values []uint16) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, value := range values {
+		if value != 0 {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+func unpackBits(packed []byte, count int) []uint16 {
+	values := make([]uint16, count)
+	for i := range values {
+		values[i] = uint16(packed[i/8]>>(i%8)) & 1
+	}
+	return values
+}
+
+// crc16 is the modbus rtu checksum, it is sent low byte first
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,258 @@
+package modbussim
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Listen after the server is closed
+var ErrClosed = errors.New("modbus simulator is closed")
+
+// Framing is how requests are framed on the connection
+type Framing string
+
+const (
+	TCP Framing = "tcp" // modbus tcp with the mbap header
+	RTU Framing = "rtu" // rtu frames with a crc sent over tcp, eg; for a serial to tcp bridge or modbus.RTUOverTCPClient
+)
+
+// Server is a modbus simulator, it answers for each device added to it on every connection it accepts
+type Server struct {
+	framing Framing
+
+	mu       sync.Mutex
+	clock    clock.Clock
+	devices  map[byte]*Device
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func NewServer(framing Framing) *Server {
+	return &Server{
+		framing: framing,
+		clock:   clock.System(),
+		devices: make(map[byte]*Device),
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+// SetClock replaces the clock scripts read the time from, tests use a fake clock
+func (s *Server) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+func (s *Server) now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clock.Now()
+}
+
+// AddDevice adds a device with the slave id, an existing device with the id is returned
+func (s *Server) AddDevice(address byte) *Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.devices[address]; ok {
+		return d
+	}
+	d := newDevice(address, s.now)
+	s.devices[address] = d
+	return d
+}
+
+// Device returns the device with the slave id, nil if there is none
+func (s *Server) Device(address byte) *Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.devices[address]
+}
+
+// Listen listens on the address and serves in the background until Close, use 127.0.0.1:0 for a free port in tests
+func (s *Server) Listen(address string) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return ErrClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		_ = s.Serve(l)
+	}()
+	return nil
+}
+
+// Addr is the address the server is listening on, nil before Listen
+func (s *Server) Addr() *net.TCPAddr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	addr, _ := s.listener.Addr().(*net.TCPAddr)
+	return addr
+}
+
+// Serve accepts connections on the listener until it is closed
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// Close stops the listener and closes every connection
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+	for {
+		var err error
+		if s.framing == RTU {
+			err = s.serveRTU(conn)
+		} else {
+			err = s.serveTCP(conn)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// handle passes the request to the device with the slave id, respond is false if there is no device or it is set to
+// time out
+func (s *Server) handle(address byte, pdu []byte) (response []byte, respond bool) {
+	d := s.Device(address)
+	if d == nil || len(pdu) == 0 {
+		return nil, false
+	}
+	return d.handle(pdu)
+}
+
+// serveTCP answers one request, the header is the transaction id, protocol id, length and unit id
+func (s *Server) serveTCP(conn net.Conn) error {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	if length < 2 || length > 254 {
+		return fmt.Errorf("bad mbap length %d", length)
+	}
+	pdu := make([]byte, length-1)
+	if _, err := io.ReadFull(conn, pdu); err != nil {
+		return err
+	}
+	response, respond := s.handle(header[6], pdu)
+	if !respond {
+		if s.Device(header[6]) != nil {
+			return nil
+		}
+		// a gateway answers for a unit that is not there
+		response = exception(pdu[0], ExceptionGatewayNoResponse)
+	}
+	adu := append(header[:4:4], 0, 0, header[6])
+	binary.BigEndian.PutUint16(adu[4:], uint16(len(response)+1))
+	_, err := conn.Write(append(adu, response...))
+	return err
+}
+
+// serveRTU answers one request, the length of a frame comes from its function code, a frame with a bad crc is dropped
+// the same as on a serial line
+func (s *Server) serveRTU(conn net.Conn) error {
+	frame := make([]byte, 2, 260)
+	if _, err := io.ReadFull(conn, frame); err != nil {
+		return err
+	}
+	var rest int
+	switch frame[1] {
+	case readCoils, readDiscreteInputs, readHoldingRegisters, readInputRegisters, writeSingleCoil, writeSingleRegister:
+		rest = 6
+	case writeMultipleCoils, writeMultipleRegisters:
+		head := make([]byte, 5)
+		if _, err := io.ReadFull(conn, head); err != nil {
+			return err
+		}
+		frame = append(frame, head...)
+		rest = int(head[4]) + 2
+	default:
+		// the length of an unknown function is not known, take what arrives with it
+		_ = conn.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+		buf := make([]byte, 256)
+		n, _ := conn.Read(buf)
+		_ = conn.SetReadDeadline(time.Time{})
+		frame = append(frame, buf[:n]...)
+	}
+	if rest > 0 {
+		tail := make([]byte, rest)
+		if _, err := io.ReadFull(conn, tail); err != nil {
+			return err
+		}
+		frame = append(frame, tail...)
+	}
+	if len(frame) < 4 || crc16(frame[:len(frame)-2]) != binary.LittleEndian.Uint16(frame[len(frame)-2:]) {
+		return nil
+	}
+	response, respond := s.handle(frame[0], frame[1:len(frame)-2])
+	if !respond {
+		return nil
+	}
+	adu := append([]byte{frame[0]}, response...)
+	_, err := conn.Write(binary.LittleEndian.AppendUint16(adu, crc16(adu)))
+	return err
+}
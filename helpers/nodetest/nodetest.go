@@ -0,0 +1,187 @@
+package nodetest
+
+import (
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"github.com/NubeIO/rxlib"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultTimeout is how long Next waits for an output and Send waits for an input to be read
+const DefaultTimeout = time.Second
+
+// Node is the exported value of a node type, eg; &Add
+type Node interface {
+	New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object
+}
+
+// ClockSetter is implemented by objects driven by time, the harness gives them its fake clock
+type ClockSetter interface {
+	SetClock(c clock.Clock)
+}
+
+// Harness runs an object on its own event bus so its behaviour can be tested end to end, messages are sent into its
+// input ports and the values it publishes are collected from a subscriber on each of its outputs
+type Harness struct {
+	t       testing.TB
+	Bus     *rxlib.EventBus
+	Object  rxlib.Object
+	Clock   *clock.Fake
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	outputs map[string]chan *rxlib.Message
+	stopped bool
+}
+
+// New creates an object of the node type with the settings, settings is the value of rxlib.Settings and can be nil
+// the object is not started, the clock starts at 2024-01-01 UTC
+func New(t testing.TB, node Node, settings any) *Harness {
+	t.Helper()
+	bus := rxlib.NewEventBus()
+	var s *rxlib.Settings
+	if settings != nil {
+		s = &rxlib.Settings{Value: settings}
+	}
+	object := node.New(fmt.Sprintf("test-%s", t.Name()), t.Name(), bus, s)
+	if object == nil {
+		t.Fatal("the node returned a nil object")
+	}
+	h := &Harness{
+		t:       t,
+		Bus:     bus,
+		Object:  object,
+		Clock:   clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Timeout: DefaultTimeout,
+		outputs: make(map[string]chan *rxlib.Message),
+	}
+	if c, ok := object.(ClockSetter); ok {
+		c.SetClock(h.Clock)
+	}
+	for _, port := range object.GetOutputs() {
+		// buffered so the bus never blocks on outputs the test does not read
+		ch := make(chan *rxlib.Message, 100)
+		bus.Subscribe(topic(object.GetUUID(), port.ID), ch)
+		h.outputs[port.ID] = ch
+	}
+	t.Cleanup(h.Stop)
+	return h
+}
+
+// topic is the event bus topic an output is published on
+func topic(objectUUID, portID string) string {
+	return fmt.Sprintf("%s-%s", objectUUID, portID)
+}
+
+// Start starts the object
+func (h *Harness) Start() *Harness {
+	h.Object.Start()
+	return h
+}
+
+// Stop deletes the object, it is called when the test finishes
+func (h *Harness) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stopped {
+		return
+	}
+	h.stopped = true
+	h.Object.Delete()
+}
+
+// Send sends a value into an input port, it fails the test if the object does not take it before the timeout
+// the input channels are buffered so Send can return before the object has handled the value
+func (h *Harness) Send(inputID string, value any) {
+	h.t.Helper()
+	ch, ok := h.Object.BusChannel(inputID)
+	if !ok {
+		h.t.Fatalf("%s has no input %s", h.Object.GetID(), inputID)
+	}
+	msg := &rxlib.Message{
+		Port: &rxlib.Port{
+			ID:        inputID,
+			Name:      inputID,
+			Value:     value,
+			Direction: rxlib.Input,
+		},
+		ObjectUUID: "nodetest",
+		ObjectID:   "nodetest",
+	}
+	select {
+	case ch <- msg:
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("timed out sending %v to %s", value, inputID)
+	}
+}
+
+// Advance moves the fake clock forward
+func (h *Harness) Advance(d time.Duration) time.Time {
+	return h.Clock.Advance(d)
+}
+
+func (h *Harness) output(outputID string) chan *rxlib.Message {
+	h.t.Helper()
+	ch, ok := h.outputs[outputID]
+	if !ok {
+		h.t.Fatalf("%s has no output %s", h.Object.GetID(), outputID)
+	}
+	return ch
+}
+
+// Next waits for the next value published on the output, it fails the test on a timeout
+func (h *Harness) Next(outputID string) any {
+	h.t.Helper()
+	select {
+	case msg := <-h.output(outputID):
+		return msg.Port.Value
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("timed out waiting for %s", outputID)
+		return nil
+	}
+}
+
+// Expect waits for the next value on the output and fails the test if it is not the expected value
+func (h *Harness) Expect(outputID string, expected any) {
+	h.t.Helper()
+	if got := h.Next(outputID); !reflect.DeepEqual(got, expected) {
+		h.t.Errorf("%s: Expected: %v (%T), Got: %v (%T)", outputID, expected, expected, got, got)
+	}
+}
+
+// Collect waits for count values on the output, the bus delivers each message on its own goroutine so values that
+// are published close together can arrive in any order
+func (h *Harness) Collect(outputID string, count int) []any {
+	h.t.Helper()
+	values := make([]any, 0, count)
+	for len(values) < count {
+		values = append(values, h.Next(outputID))
+	}
+	return values
+}
+
+// ExpectNone fails the test if a value is published on the output within wait
+func (h *Harness) ExpectNone(outputID string, wait time.Duration) {
+	h.t.Helper()
+	select {
+	case msg := <-h.output(outputID):
+		h.t.Errorf("%s: expected no value, got %v", outputID, msg.Port.Value)
+	case <-time.After(wait):
+	}
+}
+
+// Drain discards the values already published on the output and returns how many there were
+func (h *Harness) Drain(outputID string) int {
+	h.t.Helper()
+	ch := h.output(outputID)
+	for count := 0; ; count++ {
+		select {
+		case <-ch:
+		default:
+			return count
+		}
+	}
+}
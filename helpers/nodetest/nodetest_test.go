@@ -0,0 +1,67 @@
+package nodetest
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"github.com/NubeIO/rxlib"
+	"testing"
+	"time"
+)
+
+// echoObject publishes its input and the time from its clock
+type echoObject struct {
+	rxlib.Object
+	clock clock.Clock
+	stop  chan struct{}
+}
+
+func (n *echoObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo("echo", objectUUID, name, "test"), bus)
+	object.NewInputPort("input", "input", rxlib.PortTypeAny)
+	object.NewOutputPort("output", "output", rxlib.PortTypeAny)
+	object.NewOutputPort("time", "time", rxlib.PortTypeAny)
+	return &echoObject{Object: object, clock: clock.System(), stop: make(chan struct{})}
+}
+
+func (n *echoObject) SetClock(c clock.Clock) {
+	n.clock = c
+}
+
+func (n *echoObject) Start() {
+	input, _ := n.BusChannel("input")
+	go func() {
+		for {
+			select {
+			case msg := <-input:
+				n.PublishMessage(&rxlib.Port{ID: "output", Name: "output", Value: msg.Port.Value})
+				n.PublishMessage(&rxlib.Port{ID: "time", Name: "time", Value: n.clock.Now()})
+			case <-n.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (n *echoObject) Delete() {
+	close(n.stop)
+}
+
+func TestHarness(t *testing.T) {
+	h := New(t, &echoObject{}, nil).Start()
+	h.Send("input", 1.5)
+	h.Expect("output", 1.5)
+	h.Expect("time", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	h.Advance(time.Minute)
+	h.Send("input", "a")
+	h.Expect("output", "a")
+	h.Expect("time", time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC))
+	h.ExpectNone("output", 20*time.Millisecond)
+
+	h.Send("input", true)
+	h.Send("input", false)
+	values := h.Collect("output", 2)
+	if len(values) != 2 {
+		t.Errorf("Expected: 2 values, Got: %v", values)
+	}
+}
@@ -0,0 +1,90 @@
+package persist
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// Store saves small bits of object state so they survive a restart
+type Store interface {
+	Load(key string, out any) (bool, error)
+	Save(key string, value any) error
+	Delete(key string) error
+}
+
+// DefaultDir is where state is kept when no dir is given, it can be overridden with REACTIVE_NODES_DATA
+func DefaultDir() string {
+	if dir := os.Getenv("REACTIVE_NODES_DATA"); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "reactive-nodes")
+	}
+	return "data"
+}
+
+var unsafeKey = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+type fileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore keeps each key as a json file in dir, the dir is created on the first save
+func NewFileStore(dir string) Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &fileStore{dir: dir}
+}
+
+func (s *fileStore) path(key string) string {
+	return filepath.Join(s.dir, unsafeKey.ReplaceAllString(key, "_")+".json")
+}
+
+// Load reads the key into out, false is returned if nothing has been saved for the key
+func (s *fileStore) Load(key string, out any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, out)
+}
+
+// Save writes to a temp file and renames it so a power loss can not leave a half written file
+func (s *fileStore) Save(key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	path := s.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *fileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,25 @@
+package persist
+
+import "testing"
+
+func TestFileStore(t *testing.T) {
+	s := NewFileStore(t.TempDir())
+	var count int
+	found, err := s.Load("count/abc", &count)
+	if err != nil || found {
+		t.Fatalf("expected nothing saved, found: %v err: %v", found, err)
+	}
+	if err := s.Save("count/abc", 42); err != nil {
+		t.Fatal(err)
+	}
+	found, err = s.Load("count/abc", &count)
+	if err != nil || !found || count != 42 {
+		t.Fatalf("Load() = %d %v %v", count, found, err)
+	}
+	if err := s.Delete("count/abc"); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ = s.Load("count/abc", &count); found {
+		t.Errorf("expected key to be deleted")
+	}
+}
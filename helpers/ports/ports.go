@@ -0,0 +1,206 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/rxlib"
+	"reflect"
+)
+
+// what to do with a value that does not match the port
+const (
+	// Coerce converts the value to the port type and clamps it to the min and max
+	Coerce = "coerce"
+	// Reject returns an error for a value that is not already the port type or is out of range
+	Reject = "reject"
+)
+
+var ErrMismatch = errors.New("value does not match the port type")
+var ErrOutOfRange = errors.New("value is out of range")
+
+// Definition describes a port, nodes declare them once and use them to add the port and to check values before publishing
+// the host port only has the id, name, type and value, the min and max are only used by Check
+type Definition struct {
+	ID       string
+	Name     string
+	DataType rxlib.PortDataType
+	Default  any // value of the port when it is added, and published in place of a nil value
+	Min      *float64
+	Max      *float64
+	Mismatch string // Coerce or Reject
+}
+
+// New is a port with the name the same as the id that coerces mismatched values
+func New(id string, dataType rxlib.PortDataType) *Definition {
+	return &Definition{
+		ID:       id,
+		Name:     id,
+		DataType: dataType,
+		Mismatch: Coerce,
+	}
+}
+
+func Float(id string) *Definition {
+	return New(id, rxlib.PortTypeFloat)
+}
+
+func Bool(id string) *Definition {
+	return New(id, rxlib.PortTypeBool)
+}
+
+func String(id string) *Definition {
+	return New(id, rxlib.PortTypeString)
+}
+
+// Any is a port that passes any value
+func Any(id string) *Definition {
+	return New(id, rxlib.PortTypeAny)
+}
+
+func (d *Definition) WithName(name string) *Definition {
+	d.Name = name
+	return d
+}
+
+func (d *Definition) WithDefault(value any) *Definition {
+	d.Default = value
+	return d
+}
+
+// WithRange sets the min and max of a float port
+func (d *Definition) WithRange(min, max float64) *Definition {
+	d.Min = &min
+	d.Max = &max
+	return d
+}
+
+// Rejecting makes the port reject mismatched values instead of coercing them
+func (d *Definition) Rejecting() *Definition {
+	d.Mismatch = Reject
+	return d
+}
+
+// Check returns the value to publish, converted and clamped if the port coerces, or an error if it does not fit
+// ports with a type other than float, bool or string (eg; any) pass the value as it is
+func (d *Definition) Check(value any) (any, error) {
+	if value == nil {
+		if d.Default != nil {
+			return d.Default, nil
+		}
+		if d.DataType == rxlib.PortTypeAny {
+			return nil, nil
+		}
+		return nil, d.error(value, convert.ErrNil)
+	}
+	switch d.DataType {
+	case rxlib.PortTypeFloat:
+		return d.checkFloat(value)
+	case rxlib.PortTypeBool:
+		if d.Mismatch == Reject {
+			if _, ok := value.(bool); !ok {
+				return nil, d.error(value, ErrMismatch)
+			}
+			return value, nil
+		}
+		v, err := convert.ToBool(value)
+		if err != nil {
+			return nil, d.error(value, err)
+		}
+		return v, nil
+	case rxlib.PortTypeString:
+		if d.Mismatch == Reject {
+			if _, ok := value.(string); !ok {
+				return nil, d.error(value, ErrMismatch)
+			}
+			return value, nil
+		}
+		v, err := convert.ToString(value)
+		if err != nil {
+			return nil, d.error(value, err)
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+func (d *Definition) checkFloat(value any) (any, error) {
+	if d.Mismatch == Reject && !isNumber(value) {
+		return nil, d.error(value, ErrMismatch)
+	}
+	v, err := convert.ToFloat(value)
+	if err != nil {
+		return nil, d.error(value, err)
+	}
+	if d.Min != nil && v < *d.Min {
+		if d.Mismatch == Reject {
+			return nil, d.error(value, ErrOutOfRange)
+		}
+		v = *d.Min
+	}
+	if d.Max != nil && v > *d.Max {
+		if d.Mismatch == Reject {
+			return nil, d.error(value, ErrOutOfRange)
+		}
+		v = *d.Max
+	}
+	return v, nil
+}
+
+// Output returns the checked value as an output port ready to publish
+func (d *Definition) Output(value any) (*rxlib.Port, error) {
+	v, err := d.Check(value)
+	if err != nil {
+		return nil, err
+	}
+	return &rxlib.Port{
+		ID:        d.ID,
+		Name:      d.Name,
+		Value:     v,
+		Direction: rxlib.Output,
+		DataType:  d.DataType,
+	}, nil
+}
+
+// port is the port added to the object, its value is the default
+func (d *Definition) port(direction rxlib.PortDirection) *rxlib.Port {
+	return &rxlib.Port{
+		ID:        d.ID,
+		Name:      d.Name,
+		Value:     d.Default,
+		Direction: direction,
+		DataType:  d.DataType,
+	}
+}
+
+func (d *Definition) error(value any, err error) error {
+	return fmt.Errorf("port %s (%s): %T %v: %w", d.ID, d.DataType, value, value, err)
+}
+
+func isNumber(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Adder is the part of an object that ports are added to
+type Adder interface {
+	NewPort(port *rxlib.Port)
+}
+
+func AddInputs(object Adder, defs ...*Definition) {
+	for _, d := range defs {
+		object.NewPort(d.port(rxlib.Input))
+	}
+}
+
+func AddOutputs(object Adder, defs ...*Definition) {
+	for _, d := range defs {
+		object.NewPort(d.port(rxlib.Output))
+	}
+}
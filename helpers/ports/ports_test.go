@@ -0,0 +1,82 @@
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/rxlib"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		name           string
+		port           *Definition
+		value          any
+		expectedResult any
+		expectErr      error
+	}{
+		{"float", Float("out"), 1.5, 1.5, nil},
+		{"float from uint16", Float("out"), uint16(300), 300.0, nil},
+		{"float from string", Float("out"), "21.5", 21.5, nil},
+		{"float from json", Float("out"), json.Number("2"), 2.0, nil},
+		{"float bad string", Float("out"), "hot", nil, convert.ErrInvalidString},
+		{"float clamped", Float("out").WithRange(0, 100), 120, 100.0, nil},
+		{"float reject range", Float("out").WithRange(0, 100).Rejecting(), -1, nil, ErrOutOfRange},
+		{"float reject type", Float("out").Rejecting(), "1", nil, ErrMismatch},
+		{"float reject int ok", Float("out").Rejecting(), 3, 3.0, nil},
+		{"float nil", Float("out"), nil, nil, convert.ErrNil},
+		{"float default", Float("out").WithDefault(0.0), nil, 0.0, nil},
+		{"bool", Bool("out"), "on", true, nil},
+		{"bool reject", Bool("out").Rejecting(), 1, nil, ErrMismatch},
+		{"string", String("out"), 2.5, "2.5", nil},
+		{"string reject", String("out").Rejecting(), false, nil, ErrMismatch},
+		{"any", Any("out"), map[string]int{"a": 1}, map[string]int{"a": 1}, nil},
+		{"any nil", Any("out"), nil, nil, nil},
+		{"custom type", New("out", "avg-message-count"), struct{ A int }{1}, struct{ A int }{1}, nil},
+	}
+	for _, testCase := range testCases {
+		result, err := testCase.port.Check(testCase.value)
+		if !errors.Is(err, testCase.expectErr) {
+			t.Errorf("%s: Expected err: %v, Got: %v", testCase.name, testCase.expectErr, err)
+		}
+		if testCase.name == "any" {
+			continue // maps are not comparable
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("%s: Expected: %v, Got: %v", testCase.name, testCase.expectedResult, result)
+		}
+	}
+}
+
+type testAdder struct {
+	inputs, outputs map[string]*rxlib.Port
+}
+
+func (a *testAdder) NewPort(port *rxlib.Port) {
+	if port.Direction == rxlib.Input {
+		a.inputs[port.ID] = port
+	} else {
+		a.outputs[port.ID] = port
+	}
+}
+
+func TestAddAndOutput(t *testing.T) {
+	a := &testAdder{inputs: map[string]*rxlib.Port{}, outputs: map[string]*rxlib.Port{}}
+	temp := Float("temp").WithRange(-40, 120).WithDefault(20.0)
+	AddInputs(a, Bool("enable"))
+	AddOutputs(a, temp, String("error"))
+	if a.inputs["enable"].DataType != rxlib.PortTypeBool || a.outputs["temp"].DataType != rxlib.PortTypeFloat || a.outputs["error"].DataType != rxlib.PortTypeString {
+		t.Errorf("unexpected ports: %v %v", a.inputs, a.outputs)
+	}
+	if a.outputs["temp"].Value != 20.0 || a.inputs["enable"].Value != nil {
+		t.Errorf("Expected: the default as the port value, Got: %v", a.outputs["temp"].Value)
+	}
+	port, err := temp.Output("150")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port.ID != "temp" || port.Value != 120.0 || port.Direction != rxlib.Output || port.DataType != rxlib.PortTypeFloat {
+		t.Errorf("unexpected port: %+v", port)
+	}
+}
@@ -0,0 +1,438 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeString  = "string"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+	TypeAny     = "" // any json value, eg; a value compared against
+)
+
+// Schema describes the settings of an object, it builds the schema shown in the UI and checks the settings against it
+// so the UI and the object agree on what is valid
+type Schema struct {
+	title  string
+	fields []*Field
+}
+
+// Field is one setting, the key is the json name of the setting
+type Field struct {
+	Key         string
+	Title       string
+	Description string
+	Type        string
+	Default     any
+	Min         *float64
+	Max         *float64
+	MinLength   int
+	MaxLength   int
+	Enum        []string
+	EnumNames   []string
+	Required    bool
+	LiveUpdate  bool                       // the object applies a change while running, other changes need a restart
+	Items       map[string]schema.Property // array items
+	when        *condition
+}
+
+// condition limits a field to when another setting has one of the values, eg; rtu fields when transport is rtu
+type condition struct {
+	key    string
+	values []string
+}
+
+func New(title string) *Schema {
+	return &Schema{title: title}
+}
+
+func (s *Schema) Title() string {
+	return s.title
+}
+
+// Fields returns the fields in UI order
+func (s *Schema) Fields() []*Field {
+	return s.fields
+}
+
+// Field returns the field with the key, nil if there is none
+func (s *Schema) Field(key string) *Field {
+	for _, f := range s.fields {
+		if f.Key == key {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Schema) add(f *Field) *Field {
+	s.fields = append(s.fields, f)
+	return f
+}
+
+func (s *Schema) Number(key, title string, def float64) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeNumber, Default: def})
+}
+
+func (s *Schema) Integer(key, title string, def int) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeInteger, Default: def})
+}
+
+func (s *Schema) String(key, title, def string) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeString, Default: def})
+}
+
+func (s *Schema) Bool(key, title string, def bool) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeBoolean, Default: def})
+}
+
+// Enum is a string setting that must be one of the options
+func (s *Schema) Enum(key, title, def string, options ...string) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeString, Default: def, Enum: options})
+}
+
+// Array is a list setting, items describes the properties of each item for the UI, nil for a list of strings
+func (s *Schema) Array(key, title string, items map[string]schema.Property) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeArray, Items: items})
+}
+
+// Any is a setting that can be any value, eg; a number, string or bool to compare against
+func (s *Schema) Any(key, title string) *Field {
+	return s.add(&Field{Key: key, Title: title, Type: TypeAny})
+}
+
+func (f *Field) Describe(description string) *Field {
+	f.Description = description
+	return f
+}
+
+// Range sets the min and max of a number
+func (f *Field) Range(min, max float64) *Field {
+	f.Min, f.Max = &min, &max
+	return f
+}
+
+// AtLeast sets the min of a number
+func (f *Field) AtLeast(min float64) *Field {
+	f.Min = &min
+	return f
+}
+
+// Length sets the min and max length of a string, 0 for no limit
+func (f *Field) Length(min, max int) *Field {
+	f.MinLength, f.MaxLength = min, max
+	return f
+}
+
+// Require makes the setting required, an empty string counts as missing and a missing setting uses its default
+func (f *Field) Require() *Field {
+	f.Required = true
+	return f
+}
+
+// Optional clears the default of a setting that can be left empty, eg; an optional limit
+func (f *Field) Optional() *Field {
+	f.Default = nil
+	return f
+}
+
+// Live marks a setting the object can change while it is running
+func (f *Field) Live() *Field {
+	f.LiveUpdate = true
+	return f
+}
+
+// Names sets the names shown in the UI for the enum options
+func (f *Field) Names(names ...string) *Field {
+	f.EnumNames = names
+	return f
+}
+
+// When only shows and checks the field when the setting key is one of the values
+func (f *Field) When(key string, values ...string) *Field {
+	f.when = &condition{key: key, values: values}
+	return f
+}
+
+func (f *Field) property() schema.Property {
+	p := schema.Property{
+		Type:        f.Type,
+		Title:       f.Title,
+		Description: f.Description,
+		Default:     f.Default,
+		Minimum:     f.Min,
+		Maximum:     f.Max,
+	}
+	if f.MinLength > 0 {
+		p.MinLength = &f.MinLength
+	}
+	if f.MaxLength > 0 {
+		p.MaxLength = &f.MaxLength
+	}
+	if len(f.Enum) > 0 {
+		p.Enum = f.Enum
+		p.EnumNames = f.EnumNames
+	}
+	if f.Required {
+		p.Required = []string{f.Title}
+	}
+	if f.Type == TypeArray {
+		p.Items = &schema.Property{Type: TypeString}
+		if f.Items != nil {
+			p.Items = &schema.Property{Type: "object", Properties: f.Items}
+		}
+	}
+	return p
+}
+
+// Generated builds the schema for the UI, conditional fields are added under an if/then for each value they apply to
+func (s *Schema) Generated() *schema.Generated {
+	builder := schema.NewSchemaBuilder(s.title)
+	ui := schema.UI{}
+	var order []string
+	type branch struct {
+		key, value string
+	}
+	var branches []branch
+	conditional := make(map[branch]*schema.Condition)
+	for _, f := range s.fields {
+		order = append(order, f.Key)
+		if f.when == nil {
+			builder.AddProperty(f.Key, f.property())
+			continue
+		}
+		for _, value := range f.when.values {
+			b := branch{key: f.when.key, value: value}
+			then, ok := conditional[b]
+			if !ok {
+				then = &schema.Condition{Properties: make(map[string]schema.Property)}
+				conditional[b] = then
+				branches = append(branches, b)
+			}
+			then.Properties[f.Key] = f.property()
+			if f.Required {
+				then.Required = append(then.Required, f.Key)
+			}
+		}
+	}
+	for _, b := range branches {
+		builder.AddCondition(schema.ConditionalStructure{
+			If: schema.Condition{
+				Properties: map[string]schema.Property{b.key: {Const: b.value}},
+			},
+			Then: *conditional[b],
+		})
+	}
+	ui.AddUIOrder(order)
+	return &schema.Generated{
+		Schema: builder.Build(),
+		UI:     ui,
+	}
+}
+
+// FieldError is a setting that does not fit the schema
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// Values turns the settings value into a map the way it would arrive as json
+func Values(settings *rxlib.Settings) (map[string]any, error) {
+	values := make(map[string]any)
+	if settings == nil || settings.Value == nil {
+		return values, nil
+	}
+	b, err := json.Marshal(settings.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("settings must be an object: %w", err)
+	}
+	return values, nil
+}
+
+// Validate checks the values against the schema, settings not in the schema are ignored
+func (s *Schema) Validate(values map[string]any) []*FieldError {
+	var errs []*FieldError
+	for _, f := range s.fields {
+		if !s.active(f, values) {
+			continue
+		}
+		value, ok := values[f.Key]
+		if !ok || value == nil {
+			// a missing setting takes its default
+			value = f.Default
+		}
+		if value == nil || value == "" {
+			if f.Required {
+				errs = append(errs, &FieldError{Key: f.Key, Message: "is required"})
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if message := f.check(value); message != "" {
+			errs = append(errs, &FieldError{Key: f.Key, Message: message})
+		}
+	}
+	return errs
+}
+
+// active is true if the field has no condition or the setting it depends on, or its default, has one of the values
+func (s *Schema) active(f *Field, values map[string]any) bool {
+	if f.when == nil {
+		return true
+	}
+	value, ok := values[f.when.key]
+	if !ok || value == nil {
+		if depends := s.Field(f.when.key); depends != nil {
+			value = depends.Default
+		}
+	}
+	return slices.Contains(f.when.values, fmt.Sprint(value))
+}
+
+func (f *Field) check(value any) string {
+	switch f.Type {
+	case TypeNumber, TypeInteger:
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Sprintf("must be a number, got %v", value)
+		}
+		if f.Type == TypeInteger && n != math.Trunc(n) {
+			return fmt.Sprintf("must be a whole number, got %v", n)
+		}
+		if f.Min != nil && n < *f.Min {
+			return fmt.Sprintf("must be at least %v, got %v", *f.Min, n)
+		}
+		if f.Max != nil && n > *f.Max {
+			return fmt.Sprintf("must be at most %v, got %v", *f.Max, n)
+		}
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("must be a string, got %v", value)
+		}
+		if len(f.Enum) > 0 && !slices.Contains(f.Enum, str) {
+			return fmt.Sprintf("must be one of %s, got %q", strings.Join(f.Enum, ", "), str)
+		}
+		if f.MinLength > 0 && len(str) < f.MinLength {
+			return fmt.Sprintf("must be at least %d characters", f.MinLength)
+		}
+		if f.MaxLength > 0 && len(str) > f.MaxLength {
+			return fmt.Sprintf("must be at most %d characters", f.MaxLength)
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("must be true or false, got %v", value)
+		}
+	case TypeArray:
+		if _, ok := value.([]any); !ok {
+			return fmt.Sprintf("must be a list, got %v", value)
+		}
+	}
+	return ""
+}
+
+// Decode checks the settings and decodes them into out, out should be populated with its defaults before calling
+// settings that do not fit the schema are returned as errors and left at their default, the rest are still applied
+func (s *Schema) Decode(settings *rxlib.Settings, out any) []error {
+	values, err := Values(settings)
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	for _, e := range s.Validate(values) {
+		errs = append(errs, e)
+		delete(values, e.Key)
+	}
+	// a hidden field is not reported but a bad value would still stop the decode
+	for _, f := range s.fields {
+		if value, ok := values[f.Key]; ok && value != nil && !s.active(f, values) && f.check(value) != "" {
+			delete(values, f.Key)
+		}
+	}
+	if len(values) == 0 {
+		return errs
+	}
+	b, err := json.Marshal(values)
+	if err == nil {
+		err = json.Unmarshal(b, out)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to decode settings: %w", err))
+	}
+	return errs
+}
+
+// Changed returns the keys of the fields that differ between two decoded settings of the same type
+func (s *Schema) Changed(a, b any) ([]string, error) {
+	before, err := Values(&rxlib.Settings{Value: a})
+	if err != nil {
+		return nil, err
+	}
+	after, err := Values(&rxlib.Settings{Value: b})
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, f := range s.fields {
+		if !reflect.DeepEqual(before[f.Key], after[f.Key]) {
+			changed = append(changed, f.Key)
+		}
+	}
+	return changed, nil
+}
+
+// CheckKeys returns an error for each field with no matching json tag in out, so a schema can not drift from the
+// settings struct it describes, embedded structs are included
+func (s *Schema) CheckKeys(out any) []error {
+	tags := make(map[string]bool)
+	jsonTags(reflect.TypeOf(out), tags)
+	var errs []error
+	for _, f := range s.fields {
+		if !tags[f.Key] {
+			errs = append(errs, fmt.Errorf("%s: %s is not a setting", s.title, f.Key))
+		}
+		if f.when != nil && s.Field(f.when.key) == nil {
+			errs = append(errs, fmt.Errorf("%s: %s depends on %s which is not in the schema", s.title, f.Key, f.when.key))
+		}
+	}
+	return errs
+}
+
+func jsonTags(t reflect.Type, tags map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			jsonTags(field.Type, tags)
+			continue
+		}
+		if name != "" && name != "-" {
+			tags[name] = true
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package schemas
+
+import (
+	"encoding/json"
+	"github.com/NubeIO/rxlib"
+	"strings"
+	"testing"
+)
+
+type testSettings struct {
+	Transport string  `json:"transport"`
+	Host      string  `json:"host"`
+	Port      int     `json:"port"`
+	Serial    string  `json:"serialPort"`
+	BaudRate  int     `json:"baudRate"`
+	Timeout   float64 `json:"timeout"`
+	Enabled   bool    `json:"enabled"`
+}
+
+func testSchema() *Schema {
+	s := New("Network")
+	s.Enum("transport", "Transport", "tcp", "tcp", "rtu").Names("TCP", "RTU")
+	s.String("host", "Host", "localhost").Require().When("transport", "tcp")
+	s.Integer("port", "Port", 502).Range(1, 65535).When("transport", "tcp")
+	s.String("serialPort", "Serial port", "/dev/ttyUSB0").Require().When("transport", "rtu")
+	s.Integer("baudRate", "Baud rate", 9600).AtLeast(300).When("transport", "rtu")
+	s.Number("timeout", "Timeout", 1).Range(0.1, 60)
+	s.Bool("enabled", "Enabled", true)
+	return s
+}
+
+func TestValidate(t *testing.T) {
+	s := testSchema()
+	tests := []struct {
+		values   string
+		expected []string
+	}{
+		{`{}`, nil},
+		{`{"transport": "tcp", "host": "10.0.0.1", "port": 502, "timeout": 2.5, "enabled": false}`, nil},
+		{`{"transport": "udp"}`, []string{"transport: must be one of tcp, rtu"}},
+		{`{"host": "", "port": 70000}`, []string{"host: is required", "port: must be at most 65535"}},
+		{`{"port": 1.5, "timeout": "2"}`, []string{"port: must be a whole number", "timeout: must be a number"}},
+		// the rtu fields are only checked when the transport is rtu
+		{`{"baudRate": 1}`, nil},
+		{`{"transport": "rtu", "serialPort": "", "baudRate": 1}`, []string{"serialPort: is required", "baudRate: must be at least 300"}},
+		{`{"enabled": "yes"}`, []string{"enabled: must be true or false"}},
+	}
+	for _, test := range tests {
+		var values map[string]any
+		if err := json.Unmarshal([]byte(test.values), &values); err != nil {
+			t.Fatal(err)
+		}
+		errs := s.Validate(values)
+		if len(errs) != len(test.expected) {
+			t.Errorf("Values: %s, Expected: %v, Got: %v", test.values, test.expected, errs)
+			continue
+		}
+		for i, err := range errs {
+			if !strings.HasPrefix(err.Error(), test.expected[i]) {
+				t.Errorf("Values: %s, Expected: %s, Got: %v", test.values, test.expected[i], err)
+			}
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	s := testSchema()
+	out := &testSettings{Transport: "tcp", Host: "localhost", Port: 502, Timeout: 1, Enabled: true}
+	errs := s.Decode(&rxlib.Settings{Value: map[string]any{"host": "10.0.0.1", "port": 0, "timeout": 5, "baudRate": "fast"}}, out)
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "port:") {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	// the bad port keeps its default, the hidden bad baud rate is dropped and the rest is applied
+	if out.Host != "10.0.0.1" || out.Port != 502 || out.Timeout != 5 || out.BaudRate != 0 {
+		t.Errorf("unexpected settings: %+v", out)
+	}
+	if errs := s.Decode(nil, out); errs != nil {
+		t.Errorf("expected no errors for no settings: %v", errs)
+	}
+	if errs := s.Decode(&rxlib.Settings{Value: []int{1}}, out); len(errs) != 1 {
+		t.Errorf("expected an error for settings that are not an object: %v", errs)
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	g := testSchema().Generated()
+	if len(g.Properties) != 3 {
+		t.Errorf("expected 3 top level properties, got %d", len(g.Properties))
+	}
+	if len(g.AllOf) != 2 {
+		t.Fatalf("expected a condition for tcp and rtu, got %d", len(g.AllOf))
+	}
+	rtu := g.AllOf[1]
+	if rtu.If.Properties["transport"].Const != "rtu" || len(rtu.Then.Properties) != 2 || rtu.Then.Required[0] != "serialPort" {
+		t.Errorf("unexpected rtu condition: %+v", rtu)
+	}
+	if strings.Join(g.UiOrder, ",") != "transport,host,port,serialPort,baudRate,timeout,enabled" {
+		t.Errorf("unexpected ui order: %v", g.UiOrder)
+	}
+	if g.Properties["transport"].EnumNames[1] != "RTU" {
+		t.Errorf("expected the enum names to be set")
+	}
+}
+
+func TestCheckKeys(t *testing.T) {
+	if errs := testSchema().CheckKeys(&testSettings{}); errs != nil {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	s := testSchema()
+	s.Integer("dataBits", "Data bits", 8).When("mode", "rtu")
+	if errs := s.CheckKeys(&testSettings{}); len(errs) != 2 {
+		t.Errorf("expected a missing key and a missing condition, got: %v", errs)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	before := &testSettings{Transport: "tcp", Host: "localhost", Port: 502, Timeout: 1}
+	after := *before
+	after.Port = 503
+	after.Timeout = 2
+	changed, err := testSchema().Changed(before, &after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(changed, ",") != "port,timeout" {
+		t.Errorf("Expected: port,timeout, Got: %v", changed)
+	}
+	if changed, _ := testSchema().Changed(before, before); changed != nil {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+}
@@ -0,0 +1,131 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid version")
+
+// Compare compares two semantic versions like v1.2.3 or v0.0.0-20230713135356-d9fefd3ae5a5, it returns -1, 0 or 1
+// a pre-release is lower than the release it leads up to and build metadata after a + is ignored
+func Compare(a, b string) (int, error) {
+	va, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va.core {
+		if va.core[i] != vb.core[i] {
+			return compareInt(va.core[i], vb.core[i]), nil
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return 0, nil
+	case va.pre == "":
+		return 1, nil
+	case vb.pre == "":
+		return -1, nil
+	}
+	return strings.Compare(va.pre, vb.pre), nil
+}
+
+type parsed struct {
+	core [3]int
+	pre  string
+}
+
+func parse(v string) (parsed, error) {
+	var out parsed
+	s := strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, out.pre = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 || parts[0] == "" {
+		return out, fmt.Errorf("%w: %q", ErrInvalid, v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return out, fmt.Errorf("%w: %q", ErrInvalid, v)
+		}
+		out.core[i] = n
+	}
+	return out, nil
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	return 1
+}
+
+// Requirement is the oldest version of a module the plugin works with
+type Requirement struct {
+	Module string `json:"module"`
+	Min    string `json:"min"`
+}
+
+func (r Requirement) String() string {
+	return fmt.Sprintf("%s >= %s", r.Module, r.Min)
+}
+
+// ModuleVersion returns the version of a module in the build, a replaced module reports the version of its replacement
+// found is false when the module is not in the build or has no version, eg; it is replaced with a local directory
+func ModuleVersion(info *debug.BuildInfo, module string) (version string, found bool) {
+	if info == nil {
+		return "", false
+	}
+	if info.Main.Path == module {
+		return usable(&info.Main)
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == module {
+			return usable(dep)
+		}
+	}
+	return "", false
+}
+
+func usable(m *debug.Module) (string, bool) {
+	if m.Replace != nil {
+		m = m.Replace
+	}
+	if m.Version == "" || m.Version == "(devel)" {
+		return "", false
+	}
+	return m.Version, true
+}
+
+// Check checks the modules in the build against the requirements, modules without a known version are skipped so
+// development builds with local replaces still load
+func Check(info *debug.BuildInfo, requirements []Requirement) error {
+	var errs []error
+	for _, r := range requirements {
+		have, found := ModuleVersion(info, r.Module)
+		if !found {
+			continue
+		}
+		c, err := Compare(have, r.Min)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Module, err))
+			continue
+		}
+		if c < 0 {
+			errs = append(errs, fmt.Errorf("needs %s but the host has %s", r, have))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package version
+
+import (
+	"errors"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v0.0.7", "v0.0.10", -1},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v2", "v1.99.99", 1},
+		{"1.2", "v1.2.0", 0},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0", "v1.0.0-rc.1", 1},
+		{"v1.0.0-alpha", "v1.0.0-beta", -1},
+		{"v0.0.0-20230713135356-d9fefd3ae5a5", "v0.0.1", -1},
+		{"v1.2.3+build.7", "v1.2.3", 0},
+	}
+	for _, test := range tests {
+		got, err := Compare(test.a, test.b)
+		if err != nil {
+			t.Errorf("Compare(%s, %s): %v", test.a, test.b, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("Compare(%s, %s), Expected: %d, Got: %d", test.a, test.b, test.expected, got)
+		}
+	}
+	for _, invalid := range []string{"", "v", "v1.x", "v1.2.3.4", "latest"} {
+		if _, err := Compare(invalid, "v1.0.0"); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Compare(%q): expected ErrInvalid, got %v", invalid, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	requirements := []Requirement{
+		{Module: "github.com/NubeIO/reactive", Min: "v0.0.7"},
+		{Module: "github.com/NubeIO/rxlib", Min: "v0.0.2"},
+	}
+	build := func(reactive, rxlib *debug.Module) *debug.BuildInfo {
+		return &debug.BuildInfo{Main: debug.Module{Path: "github.com/NubeIO/rubix-rx"}, Deps: []*debug.Module{reactive, rxlib}}
+	}
+
+	ok := build(&debug.Module{Path: "github.com/NubeIO/reactive", Version: "v0.0.9"}, &debug.Module{Path: "github.com/NubeIO/rxlib", Version: "v0.0.2"})
+	if err := Check(ok, requirements); err != nil {
+		t.Errorf("expected a newer host to pass: %v", err)
+	}
+
+	old := build(&debug.Module{Path: "github.com/NubeIO/reactive", Version: "v0.0.5"}, &debug.Module{Path: "github.com/NubeIO/rxlib", Version: "v0.0.1"})
+	err := Check(old, requirements)
+	if err == nil {
+		t.Fatal("expected an older host to fail")
+	}
+	for _, want := range []string{"github.com/NubeIO/reactive >= v0.0.7 but the host has v0.0.5", "github.com/NubeIO/rxlib >= v0.0.2 but the host has v0.0.1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, got: %v", want, err)
+		}
+	}
+
+	// a replace to a local directory has no version so it can not be checked
+	replaced := build(&debug.Module{Path: "github.com/NubeIO/reactive", Version: "v0.0.1", Replace: &debug.Module{Path: "../reactive"}}, &debug.Module{Path: "github.com/NubeIO/rxlib", Version: "v0.0.3"})
+	if err := Check(replaced, requirements); err != nil {
+		t.Errorf("expected a local replace to be skipped: %v", err)
+	}
+	// a replace with a version uses the replacement
+	replaced.Deps[0].Replace.Version = "v0.0.6"
+	if err := Check(replaced, requirements); err == nil {
+		t.Error("expected the replacement version to be checked")
+	}
+	if err := Check(nil, requirements); err != nil {
+		t.Errorf("expected no build info to be skipped: %v", err)
+	}
+}
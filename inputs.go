@@ -0,0 +1,88 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/rxlib"
+)
+
+const minInputCount = 2
+const maxInputCount = 20
+
+// inputCountField adds the setting for the number of numbered inputs
+func inputCountField(s *schemas.Schema) *schemas.Field {
+	return s.Integer("inputCount", "Inputs", minInputCount).Range(minInputCount, maxInputCount)
+}
+
+// inputMessage is a message and the id of the input it arrived on, done is called once it has been handled
+type inputMessage struct {
+	inputID string
+	message *rxlib.Message
+	done    func()
+}
+
+// newNumberedInputs adds the inputs input-1 to input-n, the count is held between minInputCount and maxInputCount
+func newNumberedInputs(object ports.Adder, count int, dataType rxlib.PortDataType) []string {
+	if count < minInputCount {
+		count = minInputCount
+	}
+	if count > maxInputCount {
+		count = maxInputCount
+	}
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = constants.InputName(i + 1)
+		ports.AddInputs(object, ports.New(ids[i], dataType))
+	}
+	return ids
+}
+
+// mergeInputs reads all the inputs onto one channel so an object can handle them in a single goroutine
+// the readers exit once stop is closed
+func mergeInputs(n rxlib.Object, inputIDs []string, stop chan struct{}) <-chan inputMessage {
+	out := make(chan inputMessage)
+	for _, id := range inputIDs {
+		inputChannel, exists := n.BusChannel(id)
+		if !exists {
+			continue
+		}
+		go func(id string, inputChannel chan *rxlib.Message) {
+			for {
+				select {
+				case <-stop:
+					return
+				case msg, ok := <-inputChannel:
+					if !ok {
+						return
+					}
+					select {
+					case out <- inputMessage{inputID: id, message: msg, done: received(n, id)}:
+					case <-stop:
+						return
+					}
+				}
+			}
+		}(id, inputChannel)
+	}
+	return out
+}
+
+// inputBool converts the value of a bool input like enable or reset, a value that can not be converted is logged and read as false
+func inputBool(n rxlib.Object, inputID string, value any) bool {
+	v, err := convert.ToBool(value)
+	if err != nil {
+		objectLog(n).Warn("input is not a bool", "port", inputID, "value", value, "err", err)
+		return false
+	}
+	return v
+}
+
+// messageValue returns the port value of a message or nil
+func messageValue(msg *rxlib.Message) any {
+	if msg == nil || msg.Port == nil {
+		return nil
+	}
+	return msg.Port.Value
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/nodes/trigger"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+)
+
+var RisingEdge risingEdgeObject
+var FallingEdge fallingEdgeObject
+var SRLatch srLatchObject
+var RSLatch rsLatchObject
+var Toggle toggleObject
+
+const risingEdgeName = "rising-edge"
+const fallingEdgeName = "falling-edge"
+const srLatchName = "sr-latch"
+const rsLatchName = "rs-latch"
+const toggleName = "toggle"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryLogic, name: risingEdgeName, export: "RisingEdge", node: &RisingEdge})
+	registerNode(nodeRegistration{category: categoryLogic, name: fallingEdgeName, export: "FallingEdge", node: &FallingEdge})
+	registerNode(nodeRegistration{category: categoryLogic, name: srLatchName, export: "SRLatch", node: &SRLatch})
+	registerNode(nodeRegistration{category: categoryLogic, name: rsLatchName, export: "RSLatch", node: &RSLatch})
+	registerNode(nodeRegistration{category: categoryLogic, name: toggleName, export: "Toggle", node: &Toggle})
+}
+
+var latchOutput = ports.Bool(constants.Output)
+
+// latchObject feeds its bool inputs into a state machine, update is called with the latest value of every input
+// and returns the outputs to publish in order
+type latchObject struct {
+	rxlib.Object
+	inputIDs []string
+	inputs   map[string]bool
+	update   func(inputs map[string]bool, changed string) []bool
+	stop     chan struct{}
+}
+
+func newLatchObject(objectID, objectUUID, name string, bus *rxlib.EventBus, inputIDs []string, update func(inputs map[string]bool, changed string) []bool) latchObject {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(objectID, objectUUID, name, pluginName), bus)
+	for _, id := range inputIDs {
+		ports.AddInputs(object, ports.Bool(id))
+	}
+	ports.AddOutputs(object, latchOutput)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryLogic,
+	})
+	return latchObject{
+		Object:   object,
+		inputIDs: inputIDs,
+		inputs:   make(map[string]bool),
+		update:   update,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (n *latchObject) CallSchema() *schema.Generated {
+	return noSettingsSchema.Generated()
+}
+
+func (n *latchObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputs := mergeInputs(n, n.inputIDs, n.stop)
+	go func() {
+		for {
+			select {
+			case <-n.stop:
+				return
+			case in := <-inputs:
+				n.inputs[in.inputID] = inputBool(n, in.inputID, messageValue(in.message))
+				for _, out := range n.update(n.inputs, in.inputID) {
+					publishOutput(n, latchOutput, out)
+				}
+				in.done()
+			}
+		}
+	}()
+}
+
+func (n *latchObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
+// edgePulse sends a true on an edge, nothing is sent otherwise
+func edgePulse(edge bool) []bool {
+	if edge {
+		return []bool{true}
+	}
+	return nil
+}
+
+// risingEdgeObject sends a true when the input goes from false to true
+type risingEdgeObject struct {
+	latchObject
+}
+
+func (n *risingEdgeObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	edge := &trigger.RisingEdge{}
+	return &risingEdgeObject{newLatchObject(risingEdgeName, objectUUID, name, bus, []string{constants.Input}, func(inputs map[string]bool, changed string) []bool {
+		return edgePulse(edge.Update(inputs[constants.Input]))
+	})}
+}
+
+// fallingEdgeObject sends a true when the input goes from true to false
+type fallingEdgeObject struct {
+	latchObject
+}
+
+func (n *fallingEdgeObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	edge := &trigger.FallingEdge{}
+	return &fallingEdgeObject{newLatchObject(fallingEdgeName, objectUUID, name, bus, []string{constants.Input}, func(inputs map[string]bool, changed string) []bool {
+		return edgePulse(edge.Update(inputs[constants.Input]))
+	})}
+}
+
+func newLatchUpdate(latch *trigger.Latch) func(inputs map[string]bool, changed string) []bool {
+	return func(inputs map[string]bool, changed string) []bool {
+		return []bool{latch.Update(inputs[constants.Set], inputs[constants.Reset])}
+	}
+}
+
+// srLatchObject turns on with set and off with reset, set wins when both are on
+type srLatchObject struct {
+	latchObject
+}
+
+func (n *srLatchObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &srLatchObject{newLatchObject(srLatchName, objectUUID, name, bus, []string{constants.Set, constants.Reset}, newLatchUpdate(trigger.NewSR()))}
+}
+
+// rsLatchObject turns on with set and off with reset, reset wins when both are on
+type rsLatchObject struct {
+	latchObject
+}
+
+func (n *rsLatchObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &rsLatchObject{newLatchObject(rsLatchName, objectUUID, name, bus, []string{constants.Set, constants.Reset}, newLatchUpdate(trigger.NewRS()))}
+}
+
+// toggleObject flips its output on each rising edge of the input, reset turns it off
+type toggleObject struct {
+	latchObject
+}
+
+func (n *toggleObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	toggle := &trigger.Toggle{}
+	return &toggleObject{newLatchObject(toggleName, objectUUID, name, bus, []string{constants.Input, constants.Reset}, func(inputs map[string]bool, changed string) []bool {
+		if changed == constants.Reset {
+			if !inputs[constants.Reset] {
+				return nil
+			}
+			toggle.Reset()
+			return []bool{toggle.Output()}
+		}
+		before := toggle.Output()
+		if toggle.Update(inputs[constants.Input]) == before {
+			return nil
+		}
+		return []bool{toggle.Output()}
+	})}
+}
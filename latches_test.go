@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"testing"
+	"time"
+)
+
+func TestRisingEdge(t *testing.T) {
+	h := nodetest.New(t, &RisingEdge, nil).Start()
+	h.Send(constants.Input, true)
+	h.Expect(constants.Output, true)
+	// one message for each edge
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	h.Send(constants.Input, true)
+	h.Send(constants.Input, false)
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	h.Send(constants.Input, true)
+	h.Expect(constants.Output, true)
+}
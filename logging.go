@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/logger"
+	"github.com/NubeIO/rxlib"
+)
+
+// objectLog returns the logger of the object, each line has the uuid, name and type of the object and its level can be
+// changed from the log routes while it runs
+func objectLog(n rxlib.Object) *logger.Logger {
+	return logger.Default.For(n.GetUUID(), n.GetObjectName(), n.GetID())
+}
+
+// removeObjectLog drops the logger and recent lines of a deleted object
+func removeObjectLog(n rxlib.Object) {
+	logger.Default.Remove(n.GetUUID())
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/logic"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+)
+
+var And logicAndObject
+var Or logicOrObject
+var Xor logicXorObject
+var Not logicNotObject
+var Nand logicNandObject
+var Nor logicNorObject
+var Majority logicMajorityObject
+
+func init() {
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.And, export: "And", node: &And})
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.Or, export: "Or", node: &Or})
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.Xor, export: "Xor", node: &Xor})
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.Not, export: "Not", node: &Not})
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.Nand, export: "Nand", node: &Nand})
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.Nor, export: "Nor", node: &Nor})
+	registerNode(nodeRegistration{category: categoryLogic, name: logic.Majority, export: "Majority", node: &Majority})
+}
+
+var logicInput = ports.Bool(constants.Input)
+var logicOutput = ports.Bool(constants.Output)
+
+type logicSettings struct {
+	InputCount int `json:"inputCount"`
+}
+
+var logicSchema = func() *schemas.Schema {
+	s := schemas.New("Logic")
+	inputCountField(s)
+	return s
+}()
+
+func defaultLogicSettings() *logicSettings {
+	return &logicSettings{
+		InputCount: 2,
+	}
+}
+
+// logicObject runs a logical operation over its inputs each time an input changes
+// inputs that have not had a value yet are false, an input that can not be converted to a bool is sent on the error output
+type logicObject struct {
+	rxlib.Object
+	operation string
+	inputIDs  []string
+	values    map[string]bool
+	lastError string
+	loaded    *loadedSettings
+	stop      chan struct{}
+}
+
+func newLogicObject(operation, objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) logicObject {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(operation, objectUUID, name, pluginName), bus)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryLogic,
+	})
+	var inputIDs []string
+	var loaded *loadedSettings
+	if operation == logic.Not {
+		ports.AddInputs(object, logicInput)
+		inputIDs = []string{constants.Input}
+	} else {
+		s := defaultLogicSettings()
+		loaded = loadSettings(object, logicSchema, settings, s)
+		inputIDs = newNumberedInputs(object, s.InputCount, rxlib.PortTypeBool)
+	}
+	ports.AddOutputs(object, logicOutput, errorOutput)
+	return logicObject{
+		Object:    object,
+		operation: operation,
+		inputIDs:  inputIDs,
+		values:    make(map[string]bool),
+		loaded:    loaded,
+		stop:      make(chan struct{}),
+	}
+}
+
+func (n *logicObject) CallSchema() *schema.Generated {
+	return n.loaded.generated()
+}
+
+// UpdateSettings checks the new settings, the inputs are added on create so a change needs a restart
+func (n *logicObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *logicObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputs := mergeInputs(n, n.inputIDs, n.stop)
+	go func() {
+		for {
+			select {
+			case <-n.stop:
+				return
+			case in := <-inputs:
+				n.handleInput(in)
+				in.done()
+			}
+		}
+	}()
+}
+
+func (n *logicObject) handleInput(in inputMessage) {
+	value, err := convert.ToBool(messageValue(in.message))
+	if err != nil {
+		n.setError(fmt.Sprintf("%s: %v", in.inputID, err))
+		return
+	}
+	n.values[in.inputID] = value
+	inputs := make([]bool, len(n.inputIDs))
+	for i, id := range n.inputIDs {
+		inputs[i] = n.values[id]
+	}
+	result, err := logic.Calculate(n.operation, inputs)
+	if err != nil {
+		n.setError(err.Error())
+		return
+	}
+	n.setError("")
+	publishOutput(n, logicOutput, result)
+}
+
+// setError publishes the error when it changes, an empty string clears the error
+func (n *logicObject) setError(message string) {
+	if message == n.lastError {
+		return
+	}
+	n.lastError = message
+	publishOutput(n, errorOutput, message)
+}
+
+func (n *logicObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
+type logicAndObject struct {
+	logicObject
+}
+
+func (n *logicAndObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicAndObject{newLogicObject(logic.And, objectUUID, name, bus, settings)}
+}
+
+type logicOrObject struct {
+	logicObject
+}
+
+func (n *logicOrObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicOrObject{newLogicObject(logic.Or, objectUUID, name, bus, settings)}
+}
+
+type logicXorObject struct {
+	logicObject
+}
+
+func (n *logicXorObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicXorObject{newLogicObject(logic.Xor, objectUUID, name, bus, settings)}
+}
+
+type logicNotObject struct {
+	logicObject
+}
+
+func (n *logicNotObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicNotObject{newLogicObject(logic.Not, objectUUID, name, bus, settings)}
+}
+
+type logicNandObject struct {
+	logicObject
+}
+
+func (n *logicNandObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicNandObject{newLogicObject(logic.Nand, objectUUID, name, bus, settings)}
+}
+
+type logicNorObject struct {
+	logicObject
+}
+
+func (n *logicNorObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicNorObject{newLogicObject(logic.Nor, objectUUID, name, bus, settings)}
+}
+
+type logicMajorityObject struct {
+	logicObject
+}
+
+func (n *logicMajorityObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &logicMajorityObject{newLogicObject(logic.Majority, objectUUID, name, bus, settings)}
+}
@@ -2,66 +2,82 @@ package main
 
 import (
 	"fmt"
-	pprint "github.com/NubeIO/reactive-nodes/helpers/print"
-	"github.com/NubeIO/reactive-nodes/rxcli"
+	"github.com/NubeIO/reactive-nodes/helpers/version"
 	"github.com/NubeIO/reactive/plugins"
+	"runtime/debug"
+	"strings"
 )
 
 var Plugin pluginExport
 
-const pluginName = "my plugin"
-const pluginVersion = "v1.0"
+// pluginName and pluginVersion are set at build time, see upload.bash
+// go build -buildmode=plugin -ldflags "-X main.pluginName=reactive-nodes -X main.pluginVersion=v1.2.0"
+var pluginName = "reactive-nodes"
+var pluginVersion = "v0.0.0-dev"
 
-const categoryNetworkingDHCP = "networking-dhcp"
-const dhcpName = "dhcp"
+// Requires are the oldest host runtime versions the plugin works with, keep them in step with go.mod. The host can
+// look it up to check a plugin before calling Get, Get also checks them and they are listed in the description
+var Requires = []version.Requirement{
+	{Module: "github.com/NubeIO/reactive", Min: "v0.0.7"},
+	{Module: "github.com/NubeIO/rxlib", Min: "v0.0.2"},
+}
 
+const categoryNetworkingDHCP = "networking-dhcp"
+const categoryDiagnostics = "diagnostics"
+const categoryNetworking = "networking"
 const categoryTime = "time"
-const trigger = "trigger"
-const triggerExport = "Trigger"
-
 const categoryCount = "count"
-const count = "count"
-const countExport = "Count"
-
+const categoryMath = "math"
+const categoryLogic = "logic"
+const categoryComparison = "comparison"
+const categoryControl = "control"
+const categoryStream = "stream"
 const categoryModbus = "modbus"
-const modbusNetworkName = "modbus-network"
-const modbusNetworkExport = "ModbusNetwork"
-const modbusDeviceName = "modbus-device"
-const modbusDeviceExport = "ModbusDevice"
-const modbusPointName = "modbus-point"
-const modbusPointExport = "ModbusPoint"
+
+// categories in the order they are shown, the objects are registered by each node file
+var categories = []string{
+	categoryTime,
+	categoryCount,
+	categoryMath,
+	categoryLogic,
+	categoryComparison,
+	categoryControl,
+	categoryStream,
+	categoryNetworking,
+	categoryModbus,
+	categoryDiagnostics,
+}
 
 type pluginExport struct{}
 
-func (p *pluginExport) Get() *plugins.Export {
-	e := plugins.NewPlugin(pluginName, pluginVersion, "a new plugin")
+// Get returns the plugin export built from the node registry, any registration problems are joined into the error
+// nothing is returned if the host runtime is older than the plugin supports
+func (p *pluginExport) Get() (*plugins.Export, error) {
+	if err := checkHost(); err != nil {
+		return nil, err
+	}
+	e := plugins.NewPlugin(pluginName, pluginVersion, pluginDescription())
 
-	go rxcli.RxClient()
+	err := buildExport(e, categories, nodeRegistry)
+	return e, err
+}
 
-	var err error
-	e.AddCategory(categoryTime)
-	//e.AddCategory(categoryCount)
-	//e.AddCategory(categoryModbus)
-	//err = e.AddObject(categoryTime, count, countExport)
-	//if err != nil {
-	//	fmt.Println(err)
-	//}
-	err = e.AddObject(categoryTime, trigger, triggerExport)
-	if err != nil {
-		fmt.Println(err)
+// checkHost checks the host runtime versions, a plugin shares the build info of the program that loads it
+func checkHost() error {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
 	}
+	if err := version.Check(info, Requires); err != nil {
+		return fmt.Errorf("%s %s can not be loaded: %w", pluginName, pluginVersion, err)
+	}
+	return nil
+}
 
-	// modbus
-	//err = e.AddObject(categoryModbus, modbusNetworkName, modbusNetworkExport)
-	//if err != nil {
-	//	fmt.Println(err)
-	//}
-	//err = e.AddChildObject(categoryModbus, modbusNetworkName, modbusDeviceName, modbusDeviceExport)
-	//fmt.Println(err)
-	//err = e.AddChildObject(categoryModbus, modbusDeviceName, modbusPointName, modbusPointExport)
-	//fmt.Println(err)
-
-	pprint.PrintJOSN(e)
-	fmt.Println(err)
-	return e
+func pluginDescription() string {
+	requires := make([]string, len(Requires))
+	for i, r := range Requires {
+		requires[i] = r.String()
+	}
+	return fmt.Sprintf("reactive nodes, requires %s", strings.Join(requires, ", "))
 }
@@ -1,14 +1,147 @@
 package main
 
 import (
-	pprint "github.com/NubeIO/reactive-nodes/helpers/print"
+	"github.com/NubeIO/reactive-nodes/helpers/version"
+	"github.com/NubeIO/reactive/plugins"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func Test_instance_Get(t *testing.T) {
 	p := &pluginExport{}
 
-	nodes := p.Get()
+	nodes, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes.Name != pluginName || nodes.Version != pluginVersion {
+		t.Errorf("Expected: %s %s, Got: %s %s", pluginName, pluginVersion, nodes.Name, nodes.Version)
+	}
+	if got := len(nodes.GetAllObjects()); got != len(nodeRegistry) {
+		t.Errorf("expected %d objects in the export, got %d", len(nodeRegistry), got)
+	}
+	if len(nodes.Categories) != len(categories) {
+		t.Fatalf("expected %d categories in the export, got %d", len(categories), len(nodes.Categories))
+	}
+	for i, category := range nodes.Categories {
+		if category.Name != categories[i] {
+			t.Errorf("category %d, Expected: %s, Got: %s", i, categories[i], category.Name)
+		}
+	}
+}
+
+func TestRequires(t *testing.T) {
+	modules := make(map[string]bool)
+	for _, r := range Requires {
+		if modules[r.Module] {
+			t.Errorf("module %s is required more than once", r.Module)
+		}
+		modules[r.Module] = true
+		if _, err := version.Compare(r.Min, r.Min); err != nil {
+			t.Errorf("module %s has an invalid min version: %v", r.Module, err)
+		}
+		if !strings.Contains(pluginDescription(), r.String()) {
+			t.Errorf("expected the description to list %s, got %q", r, pluginDescription())
+		}
+	}
+	for _, module := range []string{"github.com/NubeIO/reactive", "github.com/NubeIO/rxlib"} {
+		if !modules[module] {
+			t.Errorf("expected a min version for %s", module)
+		}
+	}
+}
+
+// unregistered are exported node vars that are left out of the export until they are finished
+var unregistered = map[string]bool{
+	"Plugin": true,
+	"DHCP":   true,
+}
+
+// TestRegistryMatchesExports checks every exported node var is registered under its own name and type
+func TestRegistryMatchesExports(t *testing.T) {
+	exported := make(map[string]string) // var name -> type name
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					value := spec.(*ast.ValueSpec)
+					typ, ok := value.Type.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					for _, name := range value.Names {
+						if name.IsExported() && !unregistered[name.Name] {
+							exported[name.Name] = typ.Name
+						}
+					}
+				}
+			}
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, r := range nodeRegistry {
+		registered[r.export] = true
+		typeName, ok := exported[r.export]
+		if !ok {
+			t.Errorf("object %s is registered with export %s but there is no exported var with that name", r.name, r.export)
+			continue
+		}
+		if got := reflect.TypeOf(r.node).Elem().Name(); got != typeName {
+			t.Errorf("export %s is a %s but is registered with a %s", r.export, typeName, got)
+		}
+	}
+	for name := range exported {
+		if !registered[name] {
+			t.Errorf("exported var %s is not registered", name)
+		}
+	}
+}
 
-	pprint.PrintJOSN(nodes)
+func TestBuildExportErrors(t *testing.T) {
+	registrations := []nodeRegistration{
+		{category: categoryModbus, name: modbusPointName, export: "ModbusPoint", parent: modbusDeviceName, node: &ModbusPoint},
+		{category: categoryModbus, name: modbusDeviceName, export: "ModbusDevice", parent: modbusNetworkName, node: &ModbusDevice},
+		{category: categoryModbus, name: modbusNetworkName, export: "ModbusNetwork", node: &ModbusNetwork},
+		{category: categoryModbus, name: modbusNetworkName, export: "ModbusNetwork2", node: &ModbusNetwork},
+		{category: "missing", name: countName, export: "Count", node: &Count},
+		{category: categoryModbus, name: pidName, export: "PID", parent: "missing", node: &PID},
+		{category: categoryModbus, name: pulseName, export: "Pulse"},
+	}
+	e := plugins.NewPlugin(pluginName, pluginVersion, "")
+	err := buildExport(e, []string{categoryModbus}, registrations)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{
+		"object modbus-network is registered more than once",
+		`object count has an unknown category: "missing"`,
+		"object pid: parent missing is not registered",
+		"object pulse has no constructor",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, got: %v", want, err)
+		}
+	}
+	// the children are added under their parents even though they were registered first
+	category, _ := e.GetCategory(categoryModbus)
+	if len(category.Objects) != 1 || len(category.Objects[0].Children) != 1 || len(category.Objects[0].Children[0].Children) != 1 {
+		t.Fatalf("expected network > device > point, got %d objects in the category", len(category.Objects))
+	}
 }
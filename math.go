@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/math"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+)
+
+var Add mathAddObject
+var Subtract mathSubtractObject
+var Multiply mathMultiplyObject
+var Divide mathDivideObject
+var Min mathMinObject
+var Max mathMaxObject
+var Avg mathAvgObject
+
+func init() {
+	registerNode(nodeRegistration{category: categoryMath, name: math.Add, export: "Add", node: &Add})
+	registerNode(nodeRegistration{category: categoryMath, name: math.Subtract, export: "Subtract", node: &Subtract})
+	registerNode(nodeRegistration{category: categoryMath, name: math.Multiply, export: "Multiply", node: &Multiply})
+	registerNode(nodeRegistration{category: categoryMath, name: math.Divide, export: "Divide", node: &Divide})
+	registerNode(nodeRegistration{category: categoryMath, name: math.Min, export: "Min", node: &Min})
+	registerNode(nodeRegistration{category: categoryMath, name: math.Max, export: "Max", node: &Max})
+	registerNode(nodeRegistration{category: categoryMath, name: math.Avg, export: "Avg", node: &Avg})
+}
+
+var mathOutput = ports.Float(constants.Output)
+
+type mathSettings struct {
+	InputCount int `json:"inputCount"`
+}
+
+var mathSchema = func() *schemas.Schema {
+	s := schemas.New("Math")
+	inputCountField(s)
+	return s
+}()
+
+func defaultMathSettings() *mathSettings {
+	return &mathSettings{
+		InputCount: 2,
+	}
+}
+
+// mathObject runs a calculation over its inputs in order each time an input changes
+// inputs that have not had a value yet are left out unless requireAll is set, then nothing is sent until every input has a value
+// an invalid input or a failed calculation is sent on the error output
+type mathObject struct {
+	rxlib.Object
+	inputIDs   []string
+	calc       func(inputs []float64) (float64, error)
+	requireAll bool
+	values     map[string]float64
+	lastError  string
+	loaded     *loadedSettings
+	stop       chan struct{}
+}
+
+// newMathBaseObject creates the object with its output and error ports, the inputs are added by the caller
+func newMathBaseObject(objectName, objectUUID, name string, bus *rxlib.EventBus) *reactive.BaseObject {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(objectName, objectUUID, name, pluginName), bus)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryMath,
+	})
+	ports.AddOutputs(object, mathOutput, errorOutput)
+	return object
+}
+
+func newCalcObject(object rxlib.Object, inputIDs []string, calc func([]float64) (float64, error), requireAll bool) mathObject {
+	return mathObject{
+		Object:     object,
+		inputIDs:   inputIDs,
+		calc:       calc,
+		requireAll: requireAll,
+		values:     make(map[string]float64),
+		stop:       make(chan struct{}),
+	}
+}
+
+// newMathObject is an object with a configurable number of inputs for one of the math operations
+func newMathObject(operation, objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) mathObject {
+	object := newMathBaseObject(operation, objectUUID, name, bus)
+	s := defaultMathSettings()
+	loaded := loadSettings(object, mathSchema, settings, s)
+	inputIDs := newNumberedInputs(object, s.InputCount, rxlib.PortTypeFloat)
+	// the order of the inputs matters for subtract and divide, so wait for every input rather than shift them down
+	ordered := operation == math.Subtract || operation == math.Divide
+	n := newCalcObject(object, inputIDs, func(inputs []float64) (float64, error) {
+		return math.Calculate(operation, inputs)
+	}, ordered)
+	n.loaded = loaded
+	return n
+}
+
+func (n *mathObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputs := mergeInputs(n, n.inputIDs, n.stop)
+	go func() {
+		for {
+			select {
+			case <-n.stop:
+				return
+			case in := <-inputs:
+				n.handleInput(in)
+				in.done()
+			}
+		}
+	}()
+}
+
+func (n *mathObject) handleInput(in inputMessage) {
+	value, err := convert.ToFloat(messageValue(in.message))
+	if err != nil {
+		n.setError(fmt.Sprintf("%s: %v", in.inputID, err))
+		return
+	}
+	n.values[in.inputID] = value
+	var inputs []float64
+	for _, id := range n.inputIDs {
+		if v, ok := n.values[id]; ok {
+			inputs = append(inputs, v)
+		}
+	}
+	if n.requireAll && len(inputs) < len(n.inputIDs) {
+		return
+	}
+	result, err := n.calc(inputs)
+	if err != nil {
+		n.setError(err.Error())
+		return
+	}
+	n.setError("")
+	publishOutput(n, mathOutput, result)
+}
+
+// setError publishes the error when it changes, an empty string clears the error
+func (n *mathObject) setError(message string) {
+	if message == n.lastError {
+		return
+	}
+	n.lastError = message
+	publishOutput(n, errorOutput, message)
+}
+
+func (n *mathObject) CallSchema() *schema.Generated {
+	return n.loaded.generated()
+}
+
+// UpdateSettings checks the new settings, the inputs and calculation are built on create so a change needs a restart
+func (n *mathObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *mathObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
+type mathAddObject struct {
+	mathObject
+}
+
+func (n *mathAddObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathAddObject{newMathObject(math.Add, objectUUID, name, bus, settings)}
+}
+
+type mathSubtractObject struct {
+	mathObject
+}
+
+func (n *mathSubtractObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathSubtractObject{newMathObject(math.Subtract, objectUUID, name, bus, settings)}
+}
+
+type mathMultiplyObject struct {
+	mathObject
+}
+
+func (n *mathMultiplyObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathMultiplyObject{newMathObject(math.Multiply, objectUUID, name, bus, settings)}
+}
+
+type mathDivideObject struct {
+	mathObject
+}
+
+func (n *mathDivideObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathDivideObject{newMathObject(math.Divide, objectUUID, name, bus, settings)}
+}
+
+type mathMinObject struct {
+	mathObject
+}
+
+func (n *mathMinObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathMinObject{newMathObject(math.Min, objectUUID, name, bus, settings)}
+}
+
+type mathMaxObject struct {
+	mathObject
+}
+
+func (n *mathMaxObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathMaxObject{newMathObject(math.Max, objectUUID, name, bus, settings)}
+}
+
+type mathAvgObject struct {
+	mathObject
+}
+
+func (n *mathAvgObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathAvgObject{newMathObject(math.Avg, objectUUID, name, bus, settings)}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/math"
+	"github.com/NubeIO/rxlib"
+)
+
+var Expression mathExpressionObject
+
+const mathExpressionName = "expression"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryMath, name: mathExpressionName, export: "Expression", node: &Expression})
+}
+
+type mathExpressionSettings struct {
+	Expression string `json:"expression"` // eg; (a*1.8)+32
+}
+
+var mathExpressionSchema = func() *schemas.Schema {
+	s := schemas.New("Expression")
+	s.String("expression", "Expression", "a").Require().Length(1, 1000).
+		Describe("eg; (a*1.8)+32, each variable is an input, the functions are abs sqrt exp floor ceil sin cos tan asin acos atan ln log10 log pow min max round clamp scale")
+	return s
+}()
+
+// mathExpressionObject evaluates a formula over named inputs, there is an input for each variable in the formula
+// the formula is compiled once when the object is created and evaluated each time an input changes once all the inputs have a value
+type mathExpressionObject struct {
+	mathObject
+	expression *math.Expression
+}
+
+func NewMathExpressionObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := newMathBaseObject(mathExpressionName, objectUUID, name, bus)
+	s := &mathExpressionSettings{Expression: "a"}
+	loaded := loadSettings(object, mathExpressionSchema, settings, s)
+	invalid := newCalcObject(object, nil, nil, true)
+	invalid.loaded = loaded
+	expression, err := math.Compile(s.Expression)
+	if err != nil {
+		object.AddValidationResult("settings.expression", fmt.Sprintf("invalid expression %q: %v", s.Expression, err))
+		return &mathExpressionObject{mathObject: invalid}
+	}
+	inputIDs := expression.Vars()
+	if len(inputIDs) > maxInputCount {
+		object.AddValidationResult("settings.expression", fmt.Sprintf("the expression has %d variables, the max is %d", len(inputIDs), maxInputCount))
+		return &mathExpressionObject{mathObject: invalid}
+	}
+	for _, id := range inputIDs {
+		ports.AddInputs(object, ports.Float(id))
+	}
+	calc := newCalcObject(object, inputIDs, func(inputs []float64) (float64, error) {
+		vars := make(map[string]float64, len(inputs))
+		for i, id := range inputIDs {
+			vars[id] = inputs[i]
+		}
+		return expression.Eval(vars)
+	}, true)
+	calc.loaded = loaded
+	return &mathExpressionObject{
+		mathObject: calc,
+		expression: expression,
+	}
+}
+
+func (n *mathExpressionObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewMathExpressionObject(objectUUID, name, bus, settings)
+	return newObject
+}
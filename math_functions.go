@@ -0,0 +1,255 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/math"
+	"github.com/NubeIO/rxlib"
+)
+
+var Pow mathPowObject
+var Mod mathModObject
+var Sqrt mathSqrtObject
+var Abs mathAbsObject
+var Round mathRoundObject
+var Clamp mathClampObject
+var Scale mathScaleObject
+var Trig mathTrigObject
+var Log mathLogObject
+
+const mathPowName = "pow"
+const mathModName = "mod"
+const mathSqrtName = "sqrt"
+const mathAbsName = "abs"
+const mathRoundName = "round"
+const mathClampName = "clamp"
+const mathScaleName = "scale"
+const mathTrigName = "trig"
+const mathLogName = "log"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryMath, name: mathPowName, export: "Pow", node: &Pow})
+	registerNode(nodeRegistration{category: categoryMath, name: mathModName, export: "Mod", node: &Mod})
+	registerNode(nodeRegistration{category: categoryMath, name: mathSqrtName, export: "Sqrt", node: &Sqrt})
+	registerNode(nodeRegistration{category: categoryMath, name: mathAbsName, export: "Abs", node: &Abs})
+	registerNode(nodeRegistration{category: categoryMath, name: mathRoundName, export: "Round", node: &Round})
+	registerNode(nodeRegistration{category: categoryMath, name: mathClampName, export: "Clamp", node: &Clamp})
+	registerNode(nodeRegistration{category: categoryMath, name: mathScaleName, export: "Scale", node: &Scale})
+	registerNode(nodeRegistration{category: categoryMath, name: mathTrigName, export: "Trig", node: &Trig})
+	registerNode(nodeRegistration{category: categoryMath, name: mathLogName, export: "Log", node: &Log})
+}
+
+var mathInput = ports.Float(constants.Input)
+var mathInput1 = ports.Float(constants.Input1)
+var mathInput2 = ports.Float(constants.Input2)
+
+// newUnaryObject is a math object with a single input
+func newUnaryObject(objectName, objectUUID, name string, bus *rxlib.EventBus, calc func(x float64) (float64, error)) mathObject {
+	object := newMathBaseObject(objectName, objectUUID, name, bus)
+	ports.AddInputs(object, mathInput)
+	return newCalcObject(object, []string{constants.Input}, func(inputs []float64) (float64, error) {
+		return calc(inputs[0])
+	}, true)
+}
+
+// newBinaryObject is a math object with two inputs, nothing is sent until both have a value
+func newBinaryObject(objectName, objectUUID, name string, bus *rxlib.EventBus, calc func(x, y float64) (float64, error)) mathObject {
+	object := newMathBaseObject(objectName, objectUUID, name, bus)
+	ports.AddInputs(object, mathInput1, mathInput2)
+	return newCalcObject(object, []string{constants.Input1, constants.Input2}, func(inputs []float64) (float64, error) {
+		return calc(inputs[0], inputs[1])
+	}, true)
+}
+
+// loadMathSettings loads the settings and checks them by running the calculation once, problems are added as validation results
+func loadMathSettings(object *mathObject, s *schemas.Schema, settings *rxlib.Settings, out any, check func() error) {
+	object.loaded = loadSettings(object, s, settings, out)
+	if err := check(); err != nil {
+		object.AddValidationResult("settings", err.Error())
+	}
+}
+
+// mathPowObject raises input-1 to the power of input-2
+type mathPowObject struct {
+	mathObject
+}
+
+func (n *mathPowObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathPowObject{newBinaryObject(mathPowName, objectUUID, name, bus, math.Pow)}
+}
+
+// mathModObject is the remainder of input-1 divided by input-2
+type mathModObject struct {
+	mathObject
+}
+
+func (n *mathModObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathModObject{newBinaryObject(mathModName, objectUUID, name, bus, math.Mod)}
+}
+
+type mathSqrtObject struct {
+	mathObject
+}
+
+func (n *mathSqrtObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathSqrtObject{newUnaryObject(mathSqrtName, objectUUID, name, bus, math.Sqrt)}
+}
+
+type mathAbsObject struct {
+	mathObject
+}
+
+func (n *mathAbsObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &mathAbsObject{newUnaryObject(mathAbsName, objectUUID, name, bus, func(x float64) (float64, error) {
+		return math.Abs(x), nil
+	})}
+}
+
+type mathRoundSettings struct {
+	Mode     string `json:"mode"`     // round, floor or ceil
+	Decimals int    `json:"decimals"` // negative rounds to tens, hundreds...
+}
+
+var mathRoundSchema = func() *schemas.Schema {
+	s := schemas.New("Round")
+	s.Enum("mode", "Mode", math.RoundNearest, math.RoundNearest, math.RoundFloor, math.RoundCeil).Names("Nearest", "Down", "Up")
+	s.Integer("decimals", "Decimal places", 0).Range(-10, 15).Describe("negative rounds to tens, hundreds...")
+	return s
+}()
+
+type mathRoundObject struct {
+	mathObject
+}
+
+func (n *mathRoundObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	s := &mathRoundSettings{Mode: math.RoundNearest}
+	calc := func(x float64) (float64, error) {
+		return math.Round(s.Mode, x, s.Decimals)
+	}
+	object := newUnaryObject(mathRoundName, objectUUID, name, bus, calc)
+	loadMathSettings(&object, mathRoundSchema, settings, s, func() error {
+		_, err := calc(0)
+		return err
+	})
+	return &mathRoundObject{object}
+}
+
+type mathClampSettings struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+var mathClampSchema = func() *schemas.Schema {
+	s := schemas.New("Clamp")
+	s.Number("min", "Min", 0)
+	s.Number("max", "Max", 100)
+	return s
+}()
+
+type mathClampObject struct {
+	mathObject
+}
+
+func (n *mathClampObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	s := &mathClampSettings{Min: 0, Max: 100}
+	calc := func(x float64) (float64, error) {
+		return math.Clamp(x, s.Min, s.Max)
+	}
+	object := newUnaryObject(mathClampName, objectUUID, name, bus, calc)
+	loadMathSettings(&object, mathClampSchema, settings, s, func() error {
+		_, err := calc(0)
+		return err
+	})
+	return &mathClampObject{object}
+}
+
+type mathScaleSettings struct {
+	InMin  float64 `json:"inMin"`
+	InMax  float64 `json:"inMax"`
+	OutMin float64 `json:"outMin"`
+	OutMax float64 `json:"outMax"`
+}
+
+var mathScaleSchema = func() *schemas.Schema {
+	s := schemas.New("Scale")
+	s.Number("inMin", "Input min", 0)
+	s.Number("inMax", "Input max", 100)
+	s.Number("outMin", "Output min", 0)
+	s.Number("outMax", "Output max", 100)
+	return s
+}()
+
+// mathScaleObject linearly maps the input range to the output range, eg; 4-20mA to 0-100%
+type mathScaleObject struct {
+	mathObject
+}
+
+func (n *mathScaleObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	s := &mathScaleSettings{InMin: 0, InMax: 100, OutMin: 0, OutMax: 100}
+	calc := func(x float64) (float64, error) {
+		return math.Scale(x, s.InMin, s.InMax, s.OutMin, s.OutMax)
+	}
+	object := newUnaryObject(mathScaleName, objectUUID, name, bus, calc)
+	loadMathSettings(&object, mathScaleSchema, settings, s, func() error {
+		_, err := calc(0)
+		return err
+	})
+	return &mathScaleObject{object}
+}
+
+type mathTrigSettings struct {
+	Function string `json:"function"` // sin, cos, tan, asin, acos or atan
+	Degrees  bool   `json:"degrees"`  // use degrees instead of radians
+}
+
+var mathTrigSchema = func() *schemas.Schema {
+	s := schemas.New("Trig")
+	s.Enum("function", "Function", math.Sin, math.Sin, math.Cos, math.Tan, math.Asin, math.Acos, math.Atan)
+	s.Bool("degrees", "Degrees", false).Describe("use degrees instead of radians")
+	return s
+}()
+
+type mathTrigObject struct {
+	mathObject
+}
+
+func (n *mathTrigObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	s := &mathTrigSettings{Function: math.Sin}
+	calc := func(x float64) (float64, error) {
+		return math.Trig(s.Function, x, s.Degrees)
+	}
+	object := newUnaryObject(mathTrigName, objectUUID, name, bus, calc)
+	loadMathSettings(&object, mathTrigSchema, settings, s, func() error {
+		_, err := calc(0)
+		return err
+	})
+	return &mathTrigObject{object}
+}
+
+type mathLogSettings struct {
+	Base float64 `json:"base"` // 0 for the natural log
+}
+
+var mathLogSchema = func() *schemas.Schema {
+	s := schemas.New("Log")
+	s.Number("base", "Base", 10).AtLeast(0).Describe("0 for the natural log")
+	return s
+}()
+
+type mathLogObject struct {
+	mathObject
+}
+
+func (n *mathLogObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	s := &mathLogSettings{Base: 10}
+	calc := func(x float64) (float64, error) {
+		return math.Log(s.Base, x)
+	}
+	object := newUnaryObject(mathLogName, objectUUID, name, bus, calc)
+	loadMathSettings(&object, mathLogSchema, settings, s, func() error {
+		_, err := calc(1)
+		return err
+	})
+	return &mathLogObject{object}
+}
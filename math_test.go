@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/metrics"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"testing"
+	"time"
+)
+
+func TestMathAdd(t *testing.T) {
+	h := nodetest.New(t, &Add, map[string]any{"inputCount": 3}).Start()
+	h.Send(constants.Input1, 2)
+	h.Expect(constants.Output, 2.0)
+	h.Send(constants.Input2, 3.5)
+	h.Expect(constants.Output, 5.5)
+	h.Send(constants.InputName(3), "x")
+	if message, _ := h.Next(constants.Error).(string); message == "" {
+		t.Error("expected an error for an input that is not a number")
+	}
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+}
+
+func TestMathSubtractWaitsForEveryInput(t *testing.T) {
+	h := nodetest.New(t, &Subtract, nil).Start()
+	h.Send(constants.Input2, 3)
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	h.Send(constants.Input1, 10)
+	h.Expect(constants.Output, 7.0)
+	h.Send(constants.Input2, 4)
+	h.Expect(constants.Output, 6.0)
+}
+
+func TestMathExpression(t *testing.T) {
+	h := nodetest.New(t, &Expression, map[string]any{"expression": "(a*1.8)+32"}).Start()
+	h.Send("a", 100)
+	h.Expect(constants.Output, 212.0)
+}
+
+func TestMathMetrics(t *testing.T) {
+	h := nodetest.New(t, &Add, nil).Start()
+	h.Send(constants.Input1, 1)
+	h.Expect(constants.Output, 1.0)
+	h.Send(constants.Input2, 2)
+	h.Expect(constants.Output, 3.0)
+	m, ok := metrics.Default.Get(h.Object.GetUUID())
+	if !ok {
+		t.Fatal("expected metrics for the object")
+	}
+	// the time taken is recorded after the output is published
+	s := m.Snapshot()
+	for deadline := time.Now().Add(time.Second); s.Processed < 2 && time.Now().Before(deadline); s = m.Snapshot() {
+		time.Sleep(time.Millisecond)
+	}
+	if s.In[constants.Input1] != 1 || s.In[constants.Input2] != 1 || s.Out[constants.Output] != 2 {
+		t.Errorf("Expected: 1 on each input and 2 on the output, Got: in %v out %v", s.In, s.Out)
+	}
+	if s.Processed != 2 || s.LastActivity.IsZero() {
+		t.Errorf("expected 2 messages handled and a last activity time, got %+v", s)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/metrics"
+	"github.com/NubeIO/rxlib"
+	"time"
+)
+
+// objectMetrics returns the metrics of the object, they are served in the prometheus format from the metrics route
+func objectMetrics(n rxlib.Object) *metrics.Object {
+	return metrics.Default.For(n.GetUUID(), n.GetObjectName(), n.GetID())
+}
+
+// removeObjectMetrics drops the metrics of a deleted object so it is no longer scraped
+func removeObjectMetrics(n rxlib.Object) {
+	metrics.Default.Remove(n.GetUUID())
+}
+
+// received counts a message read from the input, the returned func records the time taken to handle it
+func received(n rxlib.Object, inputID string) (done func()) {
+	m := objectMetrics(n)
+	m.In(inputID)
+	start := time.Now()
+	return func() {
+		m.Processed(time.Since(start))
+	}
+}
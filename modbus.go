@@ -1,15 +1,19 @@
 package main
 
 import (
-	"encoding/json"
+	"encoding/binary"
 	"fmt"
 	"github.com/NubeIO/reactive"
 	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/metrics"
 	"github.com/NubeIO/reactive-nodes/helpers/pointers"
-	"github.com/NubeIO/reactive-nodes/rxcli"
-	"github.com/NubeIO/rxclient"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
 	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
 	"github.com/grid-x/modbus"
+	"net"
+	"strconv"
 	"time"
 )
 
@@ -17,47 +21,167 @@ var ModbusNetwork modbusNetwork
 var ModbusDevice modbusDevice
 var ModbusPoint modbusPoint
 
+const modbusNetworkName = "modbus-network"
+const modbusDeviceName = "modbus-device"
+const modbusPointName = "modbus-point"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryModbus, name: modbusNetworkName, export: "ModbusNetwork", node: &ModbusNetwork})
+	registerNode(nodeRegistration{category: categoryModbus, name: modbusDeviceName, export: "ModbusDevice", parent: modbusNetworkName, node: &ModbusDevice})
+	registerNode(nodeRegistration{category: categoryModbus, name: modbusPointName, export: "ModbusPoint", parent: modbusDeviceName, node: &ModbusPoint})
+	metrics.Default.Describe(modbusPolls, "Polls of the devices on a modbus network.")
+	metrics.Default.Describe(modbusPollSeconds, "Time taken to poll every point on a modbus network.")
+	metrics.Default.Describe(modbusReads, "Point reads by a modbus network or of a modbus point.")
+	metrics.Default.Describe(modbusReadErrors, "Point reads that failed, eg; a timeout or an exception response.")
+}
+
+// modbus driver metrics
+const (
+	modbusPolls       = "modbus_polls_total"
+	modbusPollSeconds = "modbus_poll_seconds"
+	modbusReads       = "modbus_reads_total"
+	modbusReadErrors  = "modbus_read_errors_total"
+)
+
+var modbusInput = ports.Any(constants.Input)
+var modbusOutput = ports.Float(constants.Output)
+
+const (
+	modbusTCP        = "tcp"
+	modbusRTU        = "rtu"
+	modbusRTUOverTCP = "rtu-tcp" // rtu frames over tcp, eg; a serial to ethernet gateway
+)
+
+type modbusNetworkSettings struct {
+	Transport    string `json:"transport"` // tcp, rtu or rtu-tcp
+	Host         string `json:"host"`      // tcp and rtu-tcp only
+	Port         int    `json:"port"`      // tcp and rtu-tcp only
+	SerialPort   string `json:"serialPort"`
+	BaudRate     int    `json:"baudRate"`
+	DataBits     int    `json:"dataBits"`
+	Parity       string `json:"parity"` // N, E or O
+	StopBits     int    `json:"stopBits"`
+	PollInterval int    `json:"pollInterval"` // ms between polls
+	Timeout      int    `json:"timeout"`      // ms before a request fails
+}
+
+func defaultModbusNetworkSettings() *modbusNetworkSettings {
+	return &modbusNetworkSettings{
+		Transport:    modbusTCP,
+		Host:         "localhost",
+		Port:         10502,
+		SerialPort:   "/dev/ttyUSB0",
+		BaudRate:     9600,
+		DataBits:     8,
+		Parity:       "N",
+		StopBits:     1,
+		PollInterval: 2000,
+		Timeout:      1000,
+	}
+}
+
+var modbusNetworkSchema = func() *schemas.Schema {
+	s := schemas.New("Modbus network")
+	s.Enum("transport", "Transport", modbusTCP, modbusTCP, modbusRTU, modbusRTUOverTCP).Names("TCP", "RTU", "RTU over TCP")
+	s.String("host", "Host", "localhost").Require().When("transport", modbusTCP, modbusRTUOverTCP)
+	s.Integer("port", "Port", 10502).Range(1, 65535).When("transport", modbusTCP, modbusRTUOverTCP)
+	s.String("serialPort", "Serial port", "/dev/ttyUSB0").Require().When("transport", modbusRTU)
+	s.Integer("baudRate", "Baud rate", 9600).Range(1200, 115200).When("transport", modbusRTU)
+	s.Integer("dataBits", "Data bits", 8).Range(5, 8).When("transport", modbusRTU)
+	s.Enum("parity", "Parity", "N", "N", "E", "O").Names("None", "Even", "Odd").When("transport", modbusRTU)
+	s.Integer("stopBits", "Stop bits", 1).Range(1, 2).When("transport", modbusRTU)
+	s.Integer("pollInterval", "Poll interval (ms)", 2000).AtLeast(100).Live()
+	s.Integer("timeout", "Timeout (ms)", 1000).AtLeast(10)
+	return s
+}()
+
+// modbusHandler is a tcp, rtu or rtu over tcp handler, the slave id is set before each device is polled
+type modbusHandler interface {
+	modbus.ClientHandler
+	SetSlave(slaveID byte)
+}
+
 type modbusNetwork struct {
 	rxlib.Object
+	settings     *modbusNetworkSettings
 	pollInterval time.Duration // Interval between polls
 	stopChannel  chan struct{} // Channel to signal stopping of polling
 	client       modbus.Client
-	tcpClient    *modbus.TCPClientHandler
-	rtuClient    *modbus.RTUClientHandler
-	isRTUNetwork bool
+	handler      modbusHandler
+	loaded       *loadedSettings
+	loop         *objectLoop
 }
 
 func NewModbusNetwork(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
 	object := reactive.NewBaseObject(reactive.ObjectInfo(modbusNetworkName, objectUUID, name, pluginName), bus)
-	object.NewInputPort(constants.Input, constants.Input, "any")
-	object.NewOutputPort(constants.Output, constants.Output, "float")
+	ports.AddInputs(object, modbusInput)
+	ports.AddOutputs(object, modbusOutput)
 	object.AddDefinedChildObjects(modbusDeviceName)
 	object.SetDetails(&rxlib.Details{
 		Category:   categoryModbus,
 		ObjectType: rxlib.Driver,
 	})
+	s := defaultModbusNetworkSettings()
+	loaded := loadSettings(object, modbusNetworkSchema, settings, s)
 	n := &modbusNetwork{
 		Object:       object,
-		pollInterval: time.Second * 2,
+		settings:     s,
+		pollInterval: ms(s.PollInterval),
+		stopChannel:  make(chan struct{}),
+		loaded:       loaded,
+		loop:         newObjectLoop(),
 	}
 	return n
 }
 
+func (n *modbusNetwork) CallSchema() *schema.Generated {
+	return modbusNetworkSchema.Generated()
+}
+
+// UpdateSettings applies a new poll interval while polling, the client is built on start so a change to the transport
+// or timeout needs a restart
+func (n *modbusNetwork) UpdateSettings(settings *rxlib.Settings) {
+	next, ok := n.loaded.update(n.Object, settings)
+	if !ok {
+		return
+	}
+	s := next.(*modbusNetworkSettings)
+	n.loop.apply(func() {
+		n.pollInterval = ms(s.PollInterval)
+	})
+}
+
 func (n *modbusNetwork) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
 	newObject := NewModbusNetwork(objectUUID, name, bus, settings)
 	return newObject
 }
 
+// setClient builds the client for the transport, the handler connects on the first request
 func (n *modbusNetwork) setClient() modbus.Client {
-	n.tcpClient = modbus.NewTCPClientHandler("localhost:10502")
-	client := modbus.NewClient(n.tcpClient)
-	n.tcpClient.Connect()
-	defer n.tcpClient.Close()
-	return client
+	s := n.settings
+	switch s.Transport {
+	case modbusRTU:
+		handler := modbus.NewRTUClientHandler(s.SerialPort)
+		handler.BaudRate = s.BaudRate
+		handler.DataBits = s.DataBits
+		handler.Parity = s.Parity
+		handler.StopBits = s.StopBits
+		handler.Timeout = ms(s.Timeout)
+		n.handler = handler
+	case modbusRTUOverTCP:
+		handler := modbus.NewRTUOverTCPClientHandler(net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+		handler.Timeout = ms(s.Timeout)
+		n.handler = handler
+	default:
+		handler := modbus.NewTCPClientHandler(net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+		handler.Timeout = ms(s.Timeout)
+		n.handler = handler
+	}
+	return modbus.NewClient(n.handler)
 }
 
 func (n *modbusNetwork) setDeviceAddr(addr int) {
-	n.tcpClient.SetSlave(byte(addr))
+	n.handler.SetSlave(byte(addr))
 }
 
 // Start the polling process
@@ -66,14 +190,19 @@ func (n *modbusNetwork) Start() {
 		return
 	}
 	n.client = n.setClient()
+	exit := n.loop.run()
 	go func() {
+		defer exit()
 		ticker := time.NewTicker(n.pollInterval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
 				n.pollDevices()
+			case apply := <-n.loop.updates:
+				apply()
+				ticker.Reset(n.pollInterval)
 			case <-n.stopChannel:
-				ticker.Stop()
 				return
 			}
 		}
@@ -81,9 +210,28 @@ func (n *modbusNetwork) Start() {
 	n.SetLoaded(true)
 }
 
+// Delete stops polling and closes the connection once the last poll has finished
+func (n *modbusNetwork) Delete() {
+	close(n.stopChannel)
+	n.loop.wait()
+	if n.handler != nil {
+		if err := n.handler.Close(); err != nil {
+			objectLog(n).Warn("failed to close the connection", "err", err)
+		}
+	}
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
 // pollDevices performs the Modbus read operation for each point in each device
 func (n *modbusNetwork) pollDevices() {
-
+	networkMetrics := objectMetrics(n)
+	start := time.Now()
+	defer func() {
+		networkMetrics.Add(modbusPolls, 1)
+		networkMetrics.Observe(modbusPollSeconds, time.Since(start).Seconds())
+	}()
 	devices := n.GetChildsByType(modbusDeviceName)
 	for _, device := range devices {
 		parsedDevice, ok := device.(*modbusDevice)
@@ -94,80 +242,135 @@ func (n *modbusNetwork) pollDevices() {
 		points := device.GetChildsByType(modbusPointName)
 
 		for _, point := range points {
-			mb := &pointSettings{}
-			err := point.GetDataByKey(modbusPointName, &mb)
-			if err != nil {
-				//logger.Debug("read", "func.GetDataByKey()", "err:", err.Error())
-				return
+			parsedPoint, ok := point.(*modbusPoint)
+			if !ok || parsedPoint.pointSettings == nil {
+				continue
 			}
-			fmt.Println("read-coil", "addr:", 1, "count:", 1)
-			//logger.Info("read-coil", "addr:", 1, "count:", 1)
-			data, err := n.client.ReadCoils(1, 1) // Example usage
+			value, err := n.readPoint(parsedPoint.pointSettings)
+			pointMetrics := objectMetrics(point)
+			networkMetrics.Add(modbusReads, 1)
+			pointMetrics.Add(modbusReads, 1)
 			if err != nil {
-				fmt.Println("read-coil", "addr:", 1, "count:", 1, "err:", err.Error())
-				//logger.Error()
+				networkMetrics.Add(modbusReadErrors, 1)
+				pointMetrics.Add(modbusReadErrors, 1)
+				objectLog(point).Warn("read failed", "function", parsedPoint.function(), "register", parsedPoint.register(), "device", parsedDevice.deviceAddr, "err", err)
 				continue
-			} else {
-
 			}
+			objectLog(point).Debug("read", "function", parsedPoint.function(), "register", parsedPoint.register(), "value", value)
 			// Update point value
 			device.SetLastValueChildObject(point.GetUUID(), &rxlib.Port{
 				ID:    constants.Output,
-				Value: data,
+				Value: value,
 			})
 		}
 	}
 }
 
+// readPoint reads the register of the point, a coil or discrete input is 0 or 1
+func (n *modbusNetwork) readPoint(p *pointSettings) (float64, error) {
+	var data []byte
+	var err error
+	switch p.function() {
+	case coil:
+		data, err = n.client.ReadCoils(p.register(), 1)
+	case discreteInput:
+		data, err = n.client.ReadDiscreteInputs(p.register(), 1)
+	case holdingRegister:
+		data, err = n.client.ReadHoldingRegisters(p.register(), 1)
+	case inputRegister:
+		data, err = n.client.ReadInputRegisters(p.register(), 1)
+	default:
+		return 0, fmt.Errorf("unknown function %s", p.function())
+	}
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case p.function() == coil || p.function() == discreteInput:
+		if len(data) < 1 {
+			return 0, fmt.Errorf("short response for %s %d", p.function(), p.register())
+		}
+		return float64(data[0] & 1), nil
+	case len(data) < 2:
+		return 0, fmt.Errorf("short response for %s %d", p.function(), p.register())
+	default:
+		return float64(binary.BigEndian.Uint16(data)), nil
+	}
+}
+
+type modbusDeviceSettings struct {
+	Address int `json:"address"` // the slave id of the device
+}
+
+var modbusDeviceSchema = func() *schemas.Schema {
+	s := schemas.New("Modbus device")
+	s.Integer("address", "Address", 1).Range(1, 247).Describe("the slave id of the device")
+	return s
+}()
+
 type modbusDevice struct {
 	rxlib.Object
 	deviceAddr int
+	loaded     *loadedSettings
 }
 
 func NewModbusDevice(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
 	object := reactive.NewBaseObject(reactive.ObjectInfo(modbusDeviceName, objectUUID, name, pluginName), bus)
-	object.NewInputPort(constants.Input, constants.Input, "any")
-	object.NewOutputPort(constants.Output, constants.Output, "float")
+	ports.AddInputs(object, modbusInput)
+	ports.AddOutputs(object, modbusOutput)
 	object.AddDefinedChildObjects(modbusPointName)
 	object.SetDetails(&rxlib.Details{
 		Category:   categoryModbus,
 		ObjectType: rxlib.Driver,
 		ParentID:   pointers.NewString(modbusNetworkName),
 	})
+	s := &modbusDeviceSettings{Address: 1}
+	loaded := loadSettings(object, modbusDeviceSchema, settings, s)
 	return &modbusDevice{
 		Object:     object,
-		deviceAddr: 1,
+		deviceAddr: s.Address,
+		loaded:     loaded,
 	}
 }
 
+func (n *modbusDevice) CallSchema() *schema.Generated {
+	return modbusDeviceSchema.Generated()
+}
+
+// UpdateSettings checks the new settings, the network reads the address while polling so a change needs a restart
+func (n *modbusDevice) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
 func (n *modbusDevice) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
 	newObject := NewModbusDevice(objectUUID, name, bus, settings)
 	return newObject
 }
 
+func (n *modbusDevice) Delete() {
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
 type modbusPoint struct {
 	rxlib.Object
 	*pointSettings
-	rxClient rxclient.RxClient
+	loaded *loadedSettings
 }
 
 func NewModbusPoint(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
 	object := reactive.NewBaseObject(reactive.ObjectInfo(modbusPointName, objectUUID, name, pluginName), bus)
-	object.NewInputPort(constants.Input, constants.Input, "any")
-	object.NewOutputPort(constants.Output, constants.Output, "float")
+	ports.AddInputs(object, modbusInput)
+	ports.AddOutputs(object, modbusOutput)
 	object.SetDetails(&rxlib.Details{
 		Category:   categoryModbus,
 		ObjectType: rxlib.Driver,
 		ParentID:   pointers.NewString(modbusDeviceName),
 	})
-	rx, err := rxcli.RxClient()
-	if err != nil {
-		object.AddValidationResult("rx-client-init", fmt.Sprintf("error on init: %v", err))
-	}
 	n := &modbusPoint{
 		Object:        object,
 		pointSettings: nil,
-		rxClient:      rx,
 	}
 	n.AddSettings(settings)
 	return n
@@ -178,6 +381,12 @@ func (n *modbusPoint) New(objectUUID, name string, bus *rxlib.EventBus, settings
 	return newObject
 }
 
+func (n *modbusPoint) Delete() {
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
 // RunValidation example of a validation on adding a new point
 func (n *modbusPoint) RunValidation() {
 	validation := make(map[string]any)
@@ -190,7 +399,10 @@ type functionType string
 type requestType string
 
 const (
-	coil functionType = "coil"
+	coil            functionType = "coil"
+	discreteInput   functionType = "discrete-input"
+	holdingRegister functionType = "holding-register"
+	inputRegister   functionType = "input-register"
 )
 const (
 	read requestType = "read"
@@ -202,6 +414,15 @@ type pointSettings struct {
 	Request  requestType  `json:"request"`  // e.g., "read" or "write"
 }
 
+var modbusPointSchema = func() *schemas.Schema {
+	s := schemas.New("Modbus point")
+	s.Integer("register", "Register", 3).Range(0, 65535)
+	s.Enum("function", "Function", string(coil), string(coil), string(discreteInput), string(holdingRegister), string(inputRegister)).
+		Names("Coil", "Discrete input", "Holding register", "Input register")
+	s.Enum("request", "Request", string(read), string(read)).Names("Read")
+	return s
+}()
+
 func (n *pointSettings) register() uint16 {
 	return n.Register
 }
@@ -219,13 +440,17 @@ func (n *modbusPoint) AddSettings(settings *rxlib.Settings) {
 		Function: coil,
 		Request:  read,
 	}
-
-	marshal, err := json.Marshal(settings)
-	if err != nil {
-
-		return
-	}
-	err = json.Unmarshal(marshal, &settings)
+	n.Object.AddSettings(settings)
+	n.loaded = loadSettings(n, modbusPointSchema, settings, out)
 	n.AddData(modbusPointName, out)
 	n.pointSettings = out
 }
+
+func (n *modbusPoint) CallSchema() *schema.Generated {
+	return modbusPointSchema.Generated()
+}
+
+// UpdateSettings checks the new settings, the network reads the register while polling so a change needs a restart
+func (n *modbusPoint) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
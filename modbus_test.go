@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/modbussim"
+	"github.com/NubeIO/rxlib"
+	"testing"
+	"time"
+)
+
+// TestModbusReadPoint polls each function from the simulator with the client the network builds from its settings
+// the simulator has no serial port, rtu framing is tested over tcp
+func TestModbusReadPoint(t *testing.T) {
+	transports := []struct {
+		transport string
+		framing   modbussim.Framing
+	}{
+		{modbusTCP, modbussim.TCP},
+		{modbusRTUOverTCP, modbussim.RTU},
+	}
+	for _, transport := range transports {
+		t.Run(transport.transport, func(t *testing.T) {
+			testModbusReadPoint(t, transport.transport, transport.framing)
+		})
+	}
+}
+
+func testModbusReadPoint(t *testing.T, transport string, framing modbussim.Framing) {
+	sim := modbussim.NewServer(framing)
+	d := sim.AddDevice(3)
+	for _, b := range modbussim.Banks {
+		d.AddBank(b, 0, 10)
+	}
+	_ = d.Set(modbussim.Coils, 1, 1)
+	_ = d.Set(modbussim.DiscreteInputs, 2, 1)
+	_ = d.Set(modbussim.HoldingRegisters, 3, 1234)
+	_ = d.Set(modbussim.InputRegisters, 4, 65535)
+	if err := sim.Listen("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	settings := &rxlib.Settings{Value: map[string]any{"transport": transport, "host": "127.0.0.1", "port": sim.Addr().Port, "timeout": 200}}
+	n := NewModbusNetwork("network", "network", rxlib.NewEventBus(), settings).(*modbusNetwork)
+	n.client = n.setClient()
+	defer n.handler.Close()
+	n.setDeviceAddr(3)
+
+	tests := []struct {
+		point    pointSettings
+		expected float64
+	}{
+		{pointSettings{Register: 1, Function: coil}, 1},
+		{pointSettings{Register: 2, Function: coil}, 0},
+		{pointSettings{Register: 2, Function: discreteInput}, 1},
+		{pointSettings{Register: 3, Function: holdingRegister}, 1234},
+		{pointSettings{Register: 4, Function: inputRegister}, 65535},
+	}
+	for _, test := range tests {
+		got, err := n.readPoint(&test.point)
+		if err != nil {
+			t.Errorf("%s %d: %v", test.point.Function, test.point.Register, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("%s %d Expected: %v, Got: %v", test.point.Function, test.point.Register, test.expected, got)
+		}
+	}
+
+	if _, err := n.readPoint(&pointSettings{Register: 20, Function: holdingRegister}); err == nil {
+		t.Error("expected an exception for a register outside the bank")
+	}
+	d.SetTimeout(true)
+	start := time.Now()
+	if _, err := n.readPoint(&pointSettings{Register: 3, Function: holdingRegister}); err == nil {
+		t.Error("expected a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the timeout setting to be used, took %v", elapsed)
+	}
+}
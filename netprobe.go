@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/netprobe"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"time"
+)
+
+var Ping pingObject
+var TCPCheck tcpCheckObject
+var DNSResolve dnsResolveObject
+
+const pingName = "ping"
+const tcpCheckName = "tcp-check"
+const dnsResolveName = "dns-resolve"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryNetworking, name: pingName, export: "Ping", node: &Ping})
+	registerNode(nodeRegistration{category: categoryNetworking, name: tcpCheckName, export: "TCPCheck", node: &TCPCheck})
+	registerNode(nodeRegistration{category: categoryNetworking, name: dnsResolveName, export: "DNSResolve", node: &DNSResolve})
+}
+
+// probeSettings are shared by all the network probe objects
+type probeSettings struct {
+	Target   string `json:"target"`   // ping/dns: host name or ip, tcp: host:port
+	Server   string `json:"server"`   // dns only, optional dns server host:port
+	Interval int    `json:"interval"` // ms between probes
+	Timeout  int    `json:"timeout"`  // ms before a probe is marked as failed
+	Window   int    `json:"window"`   // number of probes used for the rolling loss
+}
+
+func defaultProbeSettings() *probeSettings {
+	return &probeSettings{
+		Interval: 5000,
+		Timeout:  2000,
+		Window:   10,
+	}
+}
+
+// probeSchema builds the schema of a probe, target describes what the probe expects as the target
+func probeSchema(title, target string, server bool) *schemas.Schema {
+	s := schemas.New(title)
+	s.String("target", "Target", "").Require().Describe(target)
+	if server {
+		s.String("server", "DNS server", "").Describe("optional dns server host:port, the system resolver is used if empty")
+	}
+	s.Integer("interval", "Interval (ms)", 5000).AtLeast(100).Describe("time between probes")
+	s.Integer("timeout", "Timeout (ms)", 2000).AtLeast(1).Describe("time before a probe is marked as failed")
+	s.Integer("window", "Window", 10).Range(1, 1000).Describe("number of probes used for the rolling loss")
+	return s
+}
+
+var pingSchema = probeSchema("Ping", "host name or ip", false)
+var tcpCheckSchema = probeSchema("TCP check", "host:port", false)
+var dnsResolveSchema = probeSchema("DNS resolve", "host name to resolve", true)
+
+// netProbeObject runs a prober on an interval and publishes reachability, latency and loss
+var netProbeReachable = ports.Bool(constants.Reachable)
+var netProbeLatency = ports.Float(constants.Latency)             // ms
+var netProbeLoss = ports.Float(constants.Loss).WithRange(0, 100) // %
+
+type netProbeObject struct {
+	rxlib.Object
+	prober   netprobe.Prober
+	interval time.Duration
+	stats    *netprobe.Stats
+	loaded   *loadedSettings
+	stop     chan struct{}
+}
+
+func newNetProbeObject(objectID, objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings, settingsSchema *schemas.Schema, newProber func(s *probeSettings) (netprobe.Prober, error)) netProbeObject {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(objectID, objectUUID, name, pluginName), bus)
+	ports.AddOutputs(object, netProbeReachable, netProbeLatency, netProbeLoss)
+	object.SetDetails(&rxlib.Details{
+		Category:   categoryNetworking,
+		ObjectType: rxlib.Service,
+	})
+	object.AddObjectTypeTags(rxlib.Networking)
+
+	s := defaultProbeSettings()
+	loaded := loadSettings(object, settingsSchema, settings, s)
+	prober, err := newProber(s)
+	if err != nil {
+		object.AddValidationResult("settings", err.Error())
+	}
+	return netProbeObject{
+		Object:   object,
+		prober:   prober,
+		interval: time.Duration(s.Interval) * time.Millisecond,
+		stats:    netprobe.NewStats(s.Window),
+		loaded:   loaded,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (n *netProbeObject) CallSchema() *schema.Generated {
+	return n.loaded.generated()
+}
+
+// UpdateSettings checks the new settings, the prober is built on create so a change needs a restart
+func (n *netProbeObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *netProbeObject) Start() {
+	if n.Loaded() || n.prober == nil {
+		return
+	}
+	n.SetLoaded(true)
+	go func() {
+		ticker := time.NewTicker(n.interval)
+		defer ticker.Stop()
+		n.probe()
+		for {
+			select {
+			case <-ticker.C:
+				n.probe()
+			case <-n.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (n *netProbeObject) probe() {
+	r := n.prober.Probe(context.Background())
+	n.stats.Add(r)
+	publishOutput(n, netProbeReachable, r.Reachable)
+	publishOutput(n, netProbeLatency, float64(r.Latency)/float64(time.Millisecond))
+	publishOutput(n, netProbeLoss, n.stats.LossPercent())
+}
+
+func (n *netProbeObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
+// pingObject sends an icmp echo to the target
+type pingObject struct {
+	netProbeObject
+}
+
+func NewPingObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &pingObject{
+		netProbeObject: newNetProbeObject(pingName, objectUUID, name, bus, settings, pingSchema, func(s *probeSettings) (netprobe.Prober, error) {
+			return netprobe.NewPing(s.Target, time.Duration(s.Timeout)*time.Millisecond)
+		}),
+	}
+}
+
+func (n *pingObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewPingObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+// tcpCheckObject checks that a tcp port on the target accepts connections
+type tcpCheckObject struct {
+	netProbeObject
+}
+
+func NewTCPCheckObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &tcpCheckObject{
+		netProbeObject: newNetProbeObject(tcpCheckName, objectUUID, name, bus, settings, tcpCheckSchema, func(s *probeSettings) (netprobe.Prober, error) {
+			return netprobe.NewTCP(s.Target, time.Duration(s.Timeout)*time.Millisecond)
+		}),
+	}
+}
+
+func (n *tcpCheckObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewTCPCheckObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+// dnsResolveObject checks that the target host name resolves
+type dnsResolveObject struct {
+	netProbeObject
+}
+
+func NewDNSResolveObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &dnsResolveObject{
+		netProbeObject: newNetProbeObject(dnsResolveName, objectUUID, name, bus, settings, dnsResolveSchema, func(s *probeSettings) (netprobe.Prober, error) {
+			return netprobe.NewDNS(s.Target, s.Server, time.Duration(s.Timeout)*time.Millisecond)
+		}),
+	}
+}
+
+func (n *dnsResolveObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewDNSResolveObject(objectUUID, name, bus, settings)
+	return newObject
+}
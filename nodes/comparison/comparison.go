@@ -1 +1,300 @@
 package comparison
+
+import (
+	"errors"
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"strings"
+)
+
+const (
+	Equal          = "equal"
+	NotEqual       = "not-equal"
+	Greater        = "greater"
+	GreaterOrEqual = "greater-or-equal"
+	Less           = "less"
+	LessOrEqual    = "less-or-equal"
+	Between        = "between"
+	Outside        = "outside"
+)
+
+// the types values are coerced to before they are compared
+const (
+	TypeAuto   = "auto"
+	TypeFloat  = "float"
+	TypeString = "string"
+	TypeBool   = "bool"
+)
+
+// Config of a Comparator
+//
+// values are converted to Type with the convert package before being compared:
+//   - float: numbers, bools as 1/0 and numeric strings, anything else is an error
+//   - string: strings as they are, numbers in their shortest form eg; 1.5, bools as true/false
+//   - bool: bools, numbers, numeric strings and true/false, on/off, yes/no, false is less than true
+//   - auto: bool if either value is a bool, else float if both values are numbers or numeric strings, else string
+//
+// Hysteresis only applies to floats, once the output is true the value has to move back past the limit by the band before
+// it goes false again, eg; greater with b=20 and a band of 1 is true above 20 and stays true until a drops to 19 or below.
+// For between and outside the band has to be less than half of max-min, a wider band would never let outside go false
+type Config struct {
+	Operation  string  `json:"operation"`
+	Type       string  `json:"type"`
+	Hysteresis float64 `json:"hysteresis"`
+	Tolerance  float64 `json:"tolerance"`  // floats within the tolerance are equal
+	IgnoreCase bool    `json:"ignoreCase"` // compare strings without case
+}
+
+// Comparator compares values, it holds the last result for the hysteresis
+type Comparator struct {
+	config Config
+	state  bool
+}
+
+func New(config Config) (*Comparator, error) {
+	switch config.Operation {
+	case Equal, NotEqual, Greater, GreaterOrEqual, Less, LessOrEqual, Between, Outside:
+	default:
+		return nil, fmt.Errorf("invalid comparison operation: %s", config.Operation)
+	}
+	switch config.Type {
+	case "":
+		config.Type = TypeAuto
+	case TypeAuto, TypeFloat, TypeString, TypeBool:
+	default:
+		return nil, fmt.Errorf("invalid comparison type: %s", config.Type)
+	}
+	if config.Hysteresis < 0 || config.Tolerance < 0 {
+		return nil, errors.New("hysteresis and tolerance can not be negative")
+	}
+	return &Comparator{config: config}, nil
+}
+
+// IsRange is true for between and outside, which take a value and a min and max
+func IsRange(operation string) bool {
+	return operation == Between || operation == Outside
+}
+
+// Compare compares a to b, the comparator must not be a range
+func (c *Comparator) Compare(a, b any) (bool, error) {
+	if IsRange(c.config.Operation) {
+		return false, fmt.Errorf("%s needs a min and max", c.config.Operation)
+	}
+	kind, err := c.kind(a, b)
+	if err != nil {
+		return false, err
+	}
+	if kind == TypeFloat {
+		x, y, err := floats(a, b)
+		if err != nil {
+			return false, err
+		}
+		c.state = c.compareFloat(x, y)
+		return c.state, nil
+	}
+	cmp, err := c.compare(kind, a, b)
+	if err != nil {
+		return false, err
+	}
+	switch c.config.Operation {
+	case Equal:
+		c.state = cmp == 0
+	case NotEqual:
+		c.state = cmp != 0
+	case Greater:
+		c.state = cmp > 0
+	case GreaterOrEqual:
+		c.state = cmp >= 0
+	case Less:
+		c.state = cmp < 0
+	case LessOrEqual:
+		c.state = cmp <= 0
+	}
+	return c.state, nil
+}
+
+// CompareRange checks if x is between or outside min and max, min and max are inclusive for between
+func (c *Comparator) CompareRange(x, min, max any) (bool, error) {
+	if !IsRange(c.config.Operation) {
+		return false, fmt.Errorf("%s does not take a range", c.config.Operation)
+	}
+	kind, err := c.kind(x, min, max)
+	if err != nil {
+		return false, err
+	}
+	if kind == TypeFloat {
+		v, lo, err := floats(x, min)
+		if err != nil {
+			return false, err
+		}
+		_, hi, err := floats(x, max)
+		if err != nil {
+			return false, err
+		}
+		if lo > hi {
+			return false, fmt.Errorf("min %v is greater than max %v", lo, hi)
+		}
+		if err := c.checkBand(lo, hi); err != nil {
+			return false, err
+		}
+		c.state = c.compareRangeFloat(v, lo, hi)
+		return c.state, nil
+	}
+	cmpMin, err := c.compare(kind, x, min)
+	if err != nil {
+		return false, err
+	}
+	cmpMax, err := c.compare(kind, x, max)
+	if err != nil {
+		return false, err
+	}
+	inside := cmpMin >= 0 && cmpMax <= 0
+	c.state = inside == (c.config.Operation == Between)
+	return c.state, nil
+}
+
+// CheckRange returns an error if the hysteresis is too wide for min and max, values that are not compared as floats
+// are not checked, CompareRange reports any problem with them
+func (c *Comparator) CheckRange(min, max any) error {
+	kind, err := c.kind(min, max)
+	if err != nil || kind != TypeFloat {
+		return nil
+	}
+	lo, hi, err := floats(min, max)
+	if err != nil {
+		return nil
+	}
+	return c.checkBand(lo, hi)
+}
+
+// checkBand rejects a hysteresis of half of max-min or more, outside would stay true for every value once it was true
+func (c *Comparator) checkBand(min, max float64) error {
+	if h := c.config.Hysteresis; h > 0 && 2*h >= max-min {
+		return fmt.Errorf("hysteresis %v must be less than half of the range %v to %v", h, min, max)
+	}
+	return nil
+}
+
+// Reset clears the last result
+func (c *Comparator) Reset() {
+	c.state = false
+}
+
+func (c *Comparator) compareFloat(a, b float64) bool {
+	h := c.config.Hysteresis
+	switch c.config.Operation {
+	case Equal, NotEqual:
+		// the hysteresis state is held as equal, not-equal is the inverse
+		equal := c.state == (c.config.Operation == Equal)
+		limit := c.config.Tolerance
+		if equal {
+			limit += h
+		}
+		equal = a-b <= limit && b-a <= limit
+		return equal == (c.config.Operation == Equal)
+	case Greater:
+		if c.state {
+			return a > b-h
+		}
+		return a > b
+	case GreaterOrEqual:
+		if c.state {
+			return a >= b-h
+		}
+		return a >= b
+	case Less:
+		if c.state {
+			return a < b+h
+		}
+		return a < b
+	case LessOrEqual:
+		if c.state {
+			return a <= b+h
+		}
+		return a <= b
+	}
+	return false
+}
+
+func (c *Comparator) compareRangeFloat(x, min, max float64) bool {
+	h := c.config.Hysteresis
+	if c.config.Operation == Between {
+		if c.state {
+			return x >= min-h && x <= max+h
+		}
+		return x >= min && x <= max
+	}
+	if c.state {
+		return x < min+h || x > max-h
+	}
+	return x < min || x > max
+}
+
+// kind is the type the values are compared as
+func (c *Comparator) kind(values ...any) (string, error) {
+	for _, v := range values {
+		if v == nil {
+			return "", errors.New("can not compare a value that is not set")
+		}
+	}
+	if c.config.Type != TypeAuto {
+		return c.config.Type, nil
+	}
+	for _, v := range values {
+		if _, ok := v.(bool); ok {
+			return TypeBool, nil
+		}
+	}
+	for _, v := range values {
+		if _, err := convert.ToFloat(v); err != nil {
+			return TypeString, nil
+		}
+	}
+	return TypeFloat, nil
+}
+
+// compare returns -1, 0 or 1 for strings and bools
+func (c *Comparator) compare(kind string, a, b any) (int, error) {
+	if kind == TypeBool {
+		x, err := convert.ToBool(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := convert.ToBool(b)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case x == y:
+			return 0, nil
+		case y:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	}
+	x, err := convert.ToString(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := convert.ToString(b)
+	if err != nil {
+		return 0, err
+	}
+	if c.config.IgnoreCase {
+		x, y = strings.ToLower(x), strings.ToLower(y)
+	}
+	return strings.Compare(x, y), nil
+}
+
+func floats(a, b any) (float64, float64, error) {
+	x, err := convert.ToFloat(a)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := convert.ToFloat(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
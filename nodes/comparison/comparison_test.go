@@ -0,0 +1,141 @@
+package comparison
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		config         Config
+		a, b           any
+		expectedResult bool
+		expectErr      bool
+	}{
+		{Config{Operation: Equal}, 1, 1.0, true, false},
+		{Config{Operation: Equal}, "1.0", 1, true, false}, // numeric strings are numbers in auto
+		{Config{Operation: Equal}, json.Number("2"), float32(2), true, false},
+		{Config{Operation: Equal, Tolerance: 0.1}, 20.05, 20, true, false},
+		{Config{Operation: NotEqual}, 1, 2, true, false},
+		{Config{Operation: Greater}, 10, 9, true, false},
+		{Config{Operation: Greater}, "10", "9", true, false},
+		{Config{Operation: Greater, Type: TypeString}, "10", "9", false, false}, // compared as text
+		{Config{Operation: GreaterOrEqual}, 5, 5, true, false},
+		{Config{Operation: Less}, "apple", "banana", true, false},
+		{Config{Operation: Equal}, "ON", "on", false, false},
+		{Config{Operation: Equal, IgnoreCase: true}, "ON", "on", true, false},
+		{Config{Operation: LessOrEqual}, false, true, true, false},
+		{Config{Operation: Equal}, true, 1, true, false}, // a bool makes it a bool comparison
+		{Config{Operation: Equal}, true, "off", false, false},
+		{Config{Operation: Equal, Type: TypeString}, true, "true", true, false},
+		{Config{Operation: Equal, Type: TypeFloat}, "abc", 1, false, true},
+		{Config{Operation: Equal, Type: TypeBool}, "abc", true, false, true},
+		{Config{Operation: Equal}, nil, 1, false, true},
+		{Config{Operation: Between}, 1, 2, false, true},
+	}
+	for _, testCase := range testCases {
+		c, err := New(testCase.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := c.Compare(testCase.a, testCase.b)
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Config: %+v, A: %v, B: %v, Err: %v", testCase.config, testCase.a, testCase.b, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Config: %+v, A: %v, B: %v, Expected: %v, Got: %v", testCase.config, testCase.a, testCase.b, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestHysteresis(t *testing.T) {
+	testCases := []struct {
+		config   Config
+		b        float64
+		inputs   []float64
+		expected []bool
+	}{
+		{Config{Operation: Greater, Hysteresis: 1}, 20, []float64{19, 20.5, 19.5, 19, 20, 21}, []bool{false, true, true, false, false, true}},
+		{Config{Operation: Less, Hysteresis: 2}, 10, []float64{11, 9, 11, 12, 11}, []bool{false, true, true, false, false}},
+		{Config{Operation: GreaterOrEqual, Hysteresis: 1}, 5, []float64{5, 4, 3.9}, []bool{true, true, false}},
+		{Config{Operation: Equal, Tolerance: 0.5, Hysteresis: 0.5}, 10, []float64{11, 10.4, 10.9, 11.1, 10.9}, []bool{false, true, true, false, false}},
+		{Config{Operation: NotEqual, Tolerance: 0.5, Hysteresis: 0.5}, 10, []float64{10, 10.6, 11.1, 10.6, 9.9}, []bool{false, false, true, true, false}},
+	}
+	for _, testCase := range testCases {
+		c, err := New(testCase.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, a := range testCase.inputs {
+			result, err := c.Compare(a, testCase.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != testCase.expected[i] {
+				t.Errorf("Config: %+v, Step: %d, A: %v, Expected: %v, Got: %v", testCase.config, i, a, testCase.expected[i], result)
+			}
+		}
+	}
+}
+
+func TestCompareRange(t *testing.T) {
+	testCases := []struct {
+		config   Config
+		inputs   []any
+		expected []bool
+	}{
+		{Config{Operation: Between}, []any{0, 10, 20, 20.1}, []bool{false, true, true, false}},
+		{Config{Operation: Between, Hysteresis: 1}, []any{15, 20.5, 21.5, 20.5, 10}, []bool{true, true, false, false, true}},
+		{Config{Operation: Outside}, []any{9, 10, 20, 21}, []bool{true, false, false, true}},
+		{Config{Operation: Outside, Hysteresis: 1}, []any{9, 10.5, 11, 15}, []bool{true, true, false, false}},
+	}
+	for _, testCase := range testCases {
+		c, err := New(testCase.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, x := range testCase.inputs {
+			result, err := c.CompareRange(x, 10, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != testCase.expected[i] {
+				t.Errorf("Config: %+v, Step: %d, X: %v, Expected: %v, Got: %v", testCase.config, i, x, testCase.expected[i], result)
+			}
+		}
+	}
+	c, _ := New(Config{Operation: Between})
+	if result, _ := c.CompareRange("m", "a", "z"); !result {
+		t.Errorf("expected m to be between a and z")
+	}
+	if _, err := c.CompareRange(1, 5, 0); err == nil {
+		t.Errorf("expected an error for min greater than max")
+	}
+	if _, err := c.Compare(1, 2); err == nil {
+		t.Errorf("expected an error for compare on a range")
+	}
+	// a band of half the range or more would latch outside on
+	for _, operation := range []string{Between, Outside} {
+		c, _ := New(Config{Operation: operation, Hysteresis: 5})
+		if _, err := c.CompareRange(15, 10, 20); err == nil {
+			t.Errorf("Operation: %s, expected an error for a band of half the range", operation)
+		}
+		if err := c.CheckRange(10, 20); err == nil {
+			t.Errorf("Operation: %s, expected CheckRange to reject a band of half the range", operation)
+		}
+		if err := c.CheckRange(10, 20.5); err != nil {
+			t.Errorf("Operation: %s, expected CheckRange to pass, got %v", operation, err)
+		}
+		if err := c.CheckRange("a", "b"); err != nil {
+			t.Errorf("Operation: %s, expected strings not to be checked, got %v", operation, err)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, config := range []Config{{Operation: "similar"}, {Operation: Equal, Type: "int"}, {Operation: Less, Hysteresis: -1}} {
+		if _, err := New(config); err == nil {
+			t.Errorf("Config: %+v, expected an error", config)
+		}
+	}
+}
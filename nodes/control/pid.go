@@ -0,0 +1,127 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// ActionReverse drives the output up when the process value is below the setpoint, eg; heating
+	ActionReverse = "reverse"
+	// ActionDirect drives the output up when the process value is above the setpoint, eg; cooling
+	ActionDirect = "direct"
+)
+
+// Config of a PID loop, Ki is per second and Kd is in seconds
+type Config struct {
+	Kp       float64       `json:"kp"`
+	Ki       float64       `json:"ki"`
+	Kd       float64       `json:"kd"`
+	OutMin   float64       `json:"outMin"`
+	OutMax   float64       `json:"outMax"`
+	Action   string        `json:"action"`
+	Interval time.Duration `json:"-"`
+}
+
+// PID is a position form PID loop run at a fixed sample interval
+//
+// the derivative is taken on the process value so a setpoint change does not kick the output, the integral stops
+// while the output is saturated so it can not wind up, in manual the integral tracks the manual output so the change
+// back to auto does not bump the output
+type PID struct {
+	config   Config
+	integral float64
+	lastPV   float64
+	output   float64
+	started  bool
+	manual   bool
+}
+
+func New(config Config) (*PID, error) {
+	if config.OutMin >= config.OutMax {
+		return nil, fmt.Errorf("output min %v must be less than max %v", config.OutMin, config.OutMax)
+	}
+	if config.Interval <= 0 {
+		return nil, errors.New("the sample interval must be more than 0")
+	}
+	if config.Kp < 0 || config.Ki < 0 || config.Kd < 0 {
+		return nil, errors.New("gains can not be negative, use the action to change the direction")
+	}
+	switch config.Action {
+	case "":
+		config.Action = ActionReverse
+	case ActionReverse, ActionDirect:
+	default:
+		return nil, fmt.Errorf("invalid action: %s", config.Action)
+	}
+	return &PID{config: config, output: config.OutMin}, nil
+}
+
+// Update runs one sample and returns the output, it should be called once every interval
+func (p *PID) Update(setpoint, pv float64) float64 {
+	dt := p.config.Interval.Seconds()
+	e := setpoint - pv
+	dPV := 0.0
+	if p.started {
+		dPV = pv - p.lastPV
+	}
+	if p.config.Action == ActionDirect {
+		e = -e
+		dPV = -dPV
+	}
+	p.lastPV = pv
+	p.started = true
+	proportional := p.config.Kp * e
+	derivative := -p.config.Kd * dPV / dt
+	if p.manual {
+		// track the manual output so the change to auto is bumpless
+		p.integral = p.output - proportional - derivative
+		return p.output
+	}
+	// only integrate when it does not push the output further past a limit
+	integral := p.integral + p.config.Ki*e*dt
+	out := proportional + integral + derivative
+	if !(out > p.config.OutMax && e > 0) && !(out < p.config.OutMin && e < 0) {
+		p.integral = integral
+	}
+	p.output = p.clamp(proportional + p.integral + derivative)
+	return p.output
+}
+
+// SetManual holds the output at value until SetAuto is called
+func (p *PID) SetManual(value float64) {
+	p.manual = true
+	p.output = p.clamp(value)
+}
+
+// SetAuto goes back to auto, the output carries on from the last output
+func (p *PID) SetAuto() {
+	p.manual = false
+}
+
+func (p *PID) Manual() bool {
+	return p.manual
+}
+
+func (p *PID) Output() float64 {
+	return p.output
+}
+
+// Reset clears the integral and the last process value, the output goes to the output min
+func (p *PID) Reset() {
+	p.integral = 0
+	p.lastPV = 0
+	p.started = false
+	p.output = p.config.OutMin
+}
+
+func (p *PID) clamp(v float64) float64 {
+	if v > p.config.OutMax {
+		return p.config.OutMax
+	}
+	if v < p.config.OutMin {
+		return p.config.OutMin
+	}
+	return v
+}
@@ -0,0 +1,127 @@
+package control
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// plant is a first order process, the value moves towards gain*u with a time constant of tau seconds
+type plant struct {
+	value float64
+	gain  float64
+	tau   float64
+	dt    float64
+}
+
+func (p *plant) step(u float64) float64 {
+	p.value += (p.gain*u - p.value) * p.dt / p.tau
+	return p.value
+}
+
+func newTestPID(t *testing.T, config Config) *PID {
+	if config.Interval == 0 {
+		config.Interval = time.Second
+	}
+	pid, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pid
+}
+
+func TestPIDSettles(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+		gain   float64
+	}{
+		{"reverse", Config{Kp: 2, Ki: 0.1, Kd: 1, OutMin: 0, OutMax: 100}, 1},
+		{"direct", Config{Kp: 2, Ki: 0.1, OutMin: 0, OutMax: 100, Action: ActionDirect}, -1},
+	}
+	for _, testCase := range testCases {
+		pid := newTestPID(t, testCase.config)
+		sim := &plant{gain: testCase.gain, tau: 30, dt: 1}
+		setpoint := 50 * testCase.gain
+		maxOvershoot := 0.0
+		for i := 0; i < 600; i++ {
+			out := pid.Update(setpoint, sim.value)
+			if out < testCase.config.OutMin || out > testCase.config.OutMax {
+				t.Fatalf("%s: output %v is outside the limits", testCase.name, out)
+			}
+			sim.step(out)
+			maxOvershoot = math.Max(maxOvershoot, (sim.value-setpoint)*testCase.gain)
+		}
+		if math.Abs(sim.value-setpoint) > 0.1 {
+			t.Errorf("%s: expected the plant to settle at %v, got %v", testCase.name, setpoint, sim.value)
+		}
+		if maxOvershoot > 5 {
+			t.Errorf("%s: overshoot of %v", testCase.name, maxOvershoot)
+		}
+	}
+}
+
+func TestPIDAntiWindup(t *testing.T) {
+	pid := newTestPID(t, Config{Kp: 1, Ki: 0.2, OutMin: 0, OutMax: 10})
+	// the plant can only reach 20 so the output saturates for a long time
+	sim := &plant{gain: 2, tau: 10, dt: 1}
+	for i := 0; i < 1000; i++ {
+		sim.step(pid.Update(80, sim.value))
+	}
+	if pid.Output() != 10 {
+		t.Fatalf("expected the output to be saturated, got %v", pid.Output())
+	}
+	// once the setpoint is reachable the output should come off the limit straight away, not after unwinding 1000s of error
+	pid.Update(10, sim.value)
+	if out := pid.Update(10, sim.value); out >= 10 {
+		t.Errorf("expected the output to come off the limit, got %v", out)
+	}
+}
+
+func TestPIDBumpless(t *testing.T) {
+	pid := newTestPID(t, Config{Kp: 4, Ki: 0.5, OutMin: 0, OutMax: 100})
+	sim := &plant{gain: 1, tau: 20, dt: 1}
+	pid.SetManual(30)
+	for i := 0; i < 100; i++ {
+		if out := pid.Update(50, sim.value); out != 30 {
+			t.Fatalf("expected the manual output, got %v", out)
+		}
+		sim.step(30)
+	}
+	// the plant is at 30 with an error of 20, without tracking the first auto output would be 80+
+	// with tracking it only moves by one sample of integral, ki*error*dt = 10
+	pid.SetAuto()
+	out := pid.Update(50, sim.value)
+	if math.Abs(out-30) > 11 {
+		t.Errorf("expected a bumpless change to auto, got %v", out)
+	}
+	if pid.Manual() {
+		t.Errorf("expected auto")
+	}
+}
+
+func TestPIDDerivativeOnMeasurement(t *testing.T) {
+	pid := newTestPID(t, Config{Kp: 0, Kd: 10, OutMin: -100, OutMax: 100})
+	pid.Update(0, 20)
+	// a setpoint change alone does not move the derivative
+	if out := pid.Update(50, 20); out != 0 {
+		t.Errorf("expected no derivative kick, got %v", out)
+	}
+	// the process value rising pushes the output down on reverse action
+	if out := pid.Update(50, 21); out != -10 {
+		t.Errorf("expected -10, got %v", out)
+	}
+}
+
+func TestNewPID(t *testing.T) {
+	for _, config := range []Config{
+		{OutMin: 10, OutMax: 0, Interval: time.Second},
+		{OutMax: 1},
+		{Kp: -1, OutMax: 1, Interval: time.Second},
+		{OutMax: 1, Interval: time.Second, Action: "sideways"},
+	} {
+		if _, err := New(config); err == nil {
+			t.Errorf("Config: %+v, expected an error", config)
+		}
+	}
+}
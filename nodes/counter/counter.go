@@ -0,0 +1,128 @@
+package counter
+
+import "errors"
+
+type Direction string
+
+const (
+	Up   Direction = "up"
+	Down Direction = "down"
+)
+
+// Config for a Counter, Min and Max are optional limits
+type Config struct {
+	Start      int       `json:"startCount"`
+	Step       int       `json:"step"`
+	Direction  Direction `json:"direction"`
+	Min        *int      `json:"min,omitempty"`
+	Max        *int      `json:"max,omitempty"`
+	Rollover   bool      `json:"rollover"`   // wrap to the other limit instead of holding at the limit
+	RisingEdge bool      `json:"risingEdge"` // only count when the input goes from false to true
+}
+
+// Counter is an up/down counter with optional limits, it is not safe for use by more than one goroutine
+type Counter struct {
+	config    Config
+	count     int
+	lastInput bool
+}
+
+func New(config Config) (*Counter, error) {
+	config, err := checkConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	c := &Counter{config: config}
+	c.Set(config.Start)
+	return c, nil
+}
+
+// checkConfig fills in the defaults and checks the limits
+func checkConfig(config Config) (Config, error) {
+	if config.Step == 0 {
+		config.Step = 1
+	}
+	if config.Step < 0 {
+		return config, errors.New("counter step must be greater than 0")
+	}
+	if config.Direction == "" {
+		config.Direction = Up
+	}
+	if config.Direction != Up && config.Direction != Down {
+		return config, errors.New("counter direction must be up or down")
+	}
+	if config.Min != nil && config.Max != nil && *config.Min >= *config.Max {
+		return config, errors.New("counter min must be less than max")
+	}
+	if config.Rollover && (config.Min == nil || config.Max == nil) {
+		return config, errors.New("counter rollover needs both a min and max")
+	}
+	return config, nil
+}
+
+// Configure changes the config of a running counter, the count is kept and held within the new limits
+// the config is left as it was if the new one is invalid
+func (c *Counter) Configure(config Config) error {
+	config, err := checkConfig(config)
+	if err != nil {
+		return err
+	}
+	c.config = config
+	c.Set(c.count)
+	return nil
+}
+
+// Input handles an input message and returns true if the count changed
+// value is only used when counting on rising edges, otherwise every message is counted
+func (c *Counter) Input(value bool) bool {
+	if c.config.RisingEdge {
+		rising := value && !c.lastInput
+		c.lastInput = value
+		if !rising {
+			return false
+		}
+	}
+	before := c.count
+	if c.config.Direction == Down {
+		c.step(-c.config.Step)
+	} else {
+		c.step(c.config.Step)
+	}
+	return before != c.count
+}
+
+func (c *Counter) step(delta int) {
+	next := c.count + delta
+	if c.config.Rollover {
+		min, max := *c.config.Min, *c.config.Max
+		span := max - min + 1
+		next = min + ((next-min)%span+span)%span
+	}
+	c.Set(next)
+}
+
+// RisingEdge is true when only rising edges of the input are counted
+func (c *Counter) RisingEdge() bool {
+	return c.config.RisingEdge
+}
+
+// Count returns the current count
+func (c *Counter) Count() int {
+	return c.count
+}
+
+// Set sets the count, the value is held within the limits
+func (c *Counter) Set(value int) {
+	if c.config.Min != nil && value < *c.config.Min {
+		value = *c.config.Min
+	}
+	if c.config.Max != nil && value > *c.config.Max {
+		value = *c.config.Max
+	}
+	c.count = value
+}
+
+// Reset sets the count back to the start count
+func (c *Counter) Reset() {
+	c.Set(c.config.Start)
+}
@@ -0,0 +1,78 @@
+package counter
+
+import "testing"
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCounter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   Config
+		inputs   []bool
+		expected int
+	}{
+		{"up", Config{}, []bool{true, false, true}, 3},
+		{"down", Config{Start: 10, Direction: Down, Step: 2}, []bool{true, true}, 6},
+		{"rising edge", Config{RisingEdge: true}, []bool{true, true, false, true, false, false}, 2},
+		{"hold at max", Config{Max: intPtr(2)}, []bool{true, true, true, true}, 2},
+		{"hold at min", Config{Start: 1, Direction: Down, Min: intPtr(0)}, []bool{true, true, true}, 0},
+		{"rollover up", Config{Min: intPtr(0), Max: intPtr(3), Rollover: true}, []bool{true, true, true, true, true}, 1},
+		{"rollover down", Config{Direction: Down, Min: intPtr(0), Max: intPtr(3), Rollover: true}, []bool{true}, 3},
+	}
+	for _, testCase := range testCases {
+		c, err := New(testCase.config)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		for _, input := range testCase.inputs {
+			c.Input(input)
+		}
+		if c.Count() != testCase.expected {
+			t.Errorf("%s: Expected: %d, Got: %d", testCase.name, testCase.expected, c.Count())
+		}
+	}
+}
+
+func TestCounterReset(t *testing.T) {
+	c, _ := New(Config{Start: 5})
+	c.Input(true)
+	c.Set(100)
+	c.Reset()
+	if c.Count() != 5 {
+		t.Errorf("Expected: 5, Got: %d", c.Count())
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	configs := []Config{
+		{Step: -1},
+		{Direction: "sideways"},
+		{Min: intPtr(5), Max: intPtr(5)},
+		{Rollover: true, Max: intPtr(5)},
+	}
+	for _, config := range configs {
+		if _, err := New(config); err == nil {
+			t.Errorf("expected an error for %+v", config)
+		}
+	}
+}
+
+func TestCounterConfigure(t *testing.T) {
+	c, _ := New(Config{Start: 8})
+	if err := c.Configure(Config{Max: intPtr(5), Step: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Count() != 5 {
+		t.Errorf("Expected: 5, Got: %d", c.Count())
+	}
+	if err := c.Configure(Config{Step: -1}); err == nil {
+		t.Error("expected an error for a negative step")
+	}
+	c.Set(0)
+	c.Input(true)
+	if c.Count() != 2 {
+		t.Errorf("Expected: 2, Got: %d", c.Count())
+	}
+}
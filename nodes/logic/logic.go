@@ -0,0 +1,99 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"strings"
+)
+
+const (
+	And      = "and"
+	Or       = "or"
+	Xor      = "xor"
+	Not      = "not"
+	Nand     = "nand"
+	Nor      = "nor"
+	Majority = "majority"
+)
+
+// Define a map that associates each operation type with a function
+var operations = map[string]func([]bool) bool{
+	And:      logicalAND,
+	Or:       logicalOR,
+	Xor:      logicalXOR,
+	Not:      func(arr []bool) bool { return !arr[0] },
+	Nand:     func(arr []bool) bool { return !logicalAND(arr) },
+	Nor:      func(arr []bool) bool { return !logicalOR(arr) },
+	Majority: majority,
+}
+
+// CalculateLogicalOperation returns false for any error, use Calculate to get the error
+func CalculateLogicalOperation(operation string, inputs []interface{}) interface{} {
+	converted := make([]bool, len(inputs))
+	for i, input := range inputs {
+		v, err := convert.ToBool(input)
+		if err != nil {
+			return false
+		}
+		converted[i] = v
+	}
+	result, err := Calculate(operation, converted)
+	if err != nil {
+		return false
+	}
+	return result
+}
+
+// Calculate runs the operation over the inputs, the operation name is not case-sensitive
+// xor is true when an odd number of inputs are true, majority is true when more than half are true
+func Calculate(operation string, inputs []bool) (bool, error) {
+	operation = strings.ToLower(operation)
+	operationFunc, exists := operations[operation]
+	if !exists {
+		return false, fmt.Errorf("invalid logical operation: %s", operation)
+	}
+	if len(inputs) == 0 {
+		return false, errors.New("no input values provided")
+	}
+	if operation == Not && len(inputs) != 1 {
+		return false, fmt.Errorf("not takes one input, got %d", len(inputs))
+	}
+	return operationFunc(inputs), nil
+}
+
+func logicalAND(inputs []bool) bool {
+	for _, input := range inputs {
+		if !input {
+			return false
+		}
+	}
+	return true
+}
+
+func logicalOR(inputs []bool) bool {
+	for _, input := range inputs {
+		if input {
+			return true
+		}
+	}
+	return false
+}
+
+func logicalXOR(inputs []bool) bool {
+	result := false
+	for _, input := range inputs {
+		result = result != input
+	}
+	return result
+}
+
+func majority(inputs []bool) bool {
+	count := 0
+	for _, input := range inputs {
+		if input {
+			count++
+		}
+	}
+	return count*2 > len(inputs)
+}
@@ -0,0 +1,70 @@
+package logic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCalculateLogicalOperation(t *testing.T) {
+	testCases := []struct {
+		operation      string
+		inputs         []interface{}
+		expectedResult interface{}
+	}{
+		{"AND", []interface{}{true, true, 1.1}, true},
+		{"AND", []interface{}{true, true, 0}, false},
+		{"OR", []interface{}{2, 0, 0}, true},
+		{"OR", []interface{}{0, 0.0, false}, false},
+		{"or", []interface{}{"on", "off"}, true},
+		{"Xor", []interface{}{true, "1", json.Number("0")}, false},
+		{"and", []interface{}{true, struct{}{}}, false}, // unsupported type
+		{"and", []interface{}{true, "maybe"}, false},    // invalid string
+		{"invalid", []interface{}{true}, false},
+		{"and", []interface{}{}, false},
+	}
+	for _, testCase := range testCases {
+		result := CalculateLogicalOperation(testCase.operation, testCase.inputs)
+		if result != testCase.expectedResult {
+			t.Errorf("Operation: %s, Inputs: %v, Expected: %v, Got: %v", testCase.operation, testCase.inputs, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	testCases := []struct {
+		operation      string
+		inputs         []bool
+		expectedResult bool
+		expectErr      bool
+	}{
+		{And, []bool{true, true, true}, true, false},
+		{And, []bool{true, false, true}, false, false},
+		{Or, []bool{false, false, true}, true, false},
+		{Or, []bool{false, false}, false, false},
+		{Xor, []bool{true, false}, true, false},
+		{Xor, []bool{true, true}, false, false},
+		{Xor, []bool{true, true, true}, true, false},
+		{Not, []bool{true}, false, false},
+		{Not, []bool{false}, true, false},
+		{Not, []bool{true, false}, false, true},
+		{Nand, []bool{true, true}, false, false},
+		{Nand, []bool{true, false}, true, false},
+		{Nor, []bool{false, false}, true, false},
+		{Nor, []bool{false, true}, false, false},
+		{Majority, []bool{true, true, false}, true, false},
+		{Majority, []bool{true, false, false}, false, false},
+		{Majority, []bool{true, true, false, false}, false, false}, // a tie is not a majority
+		{"NAND", []bool{false, false}, true, false},
+		{"invalid", []bool{true}, false, true},
+		{And, nil, false, true},
+	}
+	for _, testCase := range testCases {
+		result, err := Calculate(testCase.operation, testCase.inputs)
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Operation: %s, Inputs: %v, Err: %v", testCase.operation, testCase.inputs, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Operation: %s, Inputs: %v, Expected: %v, Got: %v", testCase.operation, testCase.inputs, testCase.expectedResult, result)
+		}
+	}
+}
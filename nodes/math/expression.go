@@ -0,0 +1,407 @@
+package math
+
+import (
+	"fmt"
+	gomath "math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a compiled formula like (a*1.8)+32, compile it once and call Eval on each input change
+//
+// supported: + - * / % ^ (power), unary minus, brackets, the constants pi and e and the functions
+// abs sqrt exp floor ceil sin cos tan asin acos atan ln log10 log(x, base) pow(x, y) min(a, b...) max(a, b...)
+// round(x, decimals) clamp(x, min, max) scale(x, inMin, inMax, outMin, outMax)
+type Expression struct {
+	source string
+	vars   []string
+	eval   evalFunc
+}
+
+type evalFunc func(vars map[string]float64) (float64, error)
+
+var expressionConstants = map[string]float64{
+	"pi": gomath.Pi,
+	"e":  gomath.E,
+}
+
+type expressionFunction struct {
+	minArgs int
+	maxArgs int // -1 for no limit
+	call    func(args []float64) (float64, error)
+}
+
+func unary(f func(float64) float64) expressionFunction {
+	return expressionFunction{minArgs: 1, maxArgs: 1, call: func(args []float64) (float64, error) {
+		return checkResult(f(args[0]))
+	}}
+}
+
+var expressionFunctions = map[string]expressionFunction{
+	"abs":   unary(gomath.Abs),
+	"exp":   unary(gomath.Exp),
+	"floor": unary(gomath.Floor),
+	"ceil":  unary(gomath.Ceil),
+	"sin":   unary(gomath.Sin),
+	"cos":   unary(gomath.Cos),
+	"tan":   unary(gomath.Tan),
+	"asin":  unary(gomath.Asin),
+	"acos":  unary(gomath.Acos),
+	"atan":  unary(gomath.Atan),
+	"sqrt": {minArgs: 1, maxArgs: 1, call: func(args []float64) (float64, error) {
+		return Sqrt(args[0])
+	}},
+	"ln": {minArgs: 1, maxArgs: 1, call: func(args []float64) (float64, error) {
+		return Log(0, args[0])
+	}},
+	"log10": {minArgs: 1, maxArgs: 1, call: func(args []float64) (float64, error) {
+		return Log(10, args[0])
+	}},
+	"log": {minArgs: 2, maxArgs: 2, call: func(args []float64) (float64, error) {
+		return Log(args[1], args[0])
+	}},
+	"pow": {minArgs: 2, maxArgs: 2, call: func(args []float64) (float64, error) {
+		return Pow(args[0], args[1])
+	}},
+	"round": {minArgs: 1, maxArgs: 2, call: func(args []float64) (float64, error) {
+		decimals := 0
+		if len(args) == 2 {
+			decimals = int(args[1])
+		}
+		return Round(RoundNearest, args[0], decimals)
+	}},
+	"clamp": {minArgs: 3, maxArgs: 3, call: func(args []float64) (float64, error) {
+		return Clamp(args[0], args[1], args[2])
+	}},
+	"scale": {minArgs: 5, maxArgs: 5, call: func(args []float64) (float64, error) {
+		return Scale(args[0], args[1], args[2], args[3], args[4])
+	}},
+	"min": {minArgs: 1, maxArgs: -1, call: func(args []float64) (float64, error) {
+		return minimum(args), nil
+	}},
+	"max": {minArgs: 1, maxArgs: -1, call: func(args []float64) (float64, error) {
+		return maximum(args), nil
+	}},
+}
+
+// Compile parses the formula, the variables are every name used that is not a constant or function
+func Compile(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, seen: make(map[string]bool)}
+	eval, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return &Expression{source: source, vars: p.vars, eval: eval}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Vars returns the variable names in the order they are first used
+func (e *Expression) Vars() []string {
+	return append([]string(nil), e.vars...)
+}
+
+// Eval runs the formula, an error is returned if a variable is missing or the result is not a number
+func (e *Expression) Eval(vars map[string]float64) (float64, error) {
+	result, err := e.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	return checkResult(result)
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenIdent
+	tokenOperator
+	tokenEOF
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	pos    int
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// exponent eg; 1.5e-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			text := string(runes[start:i])
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, number: v, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		case strings.ContainsRune("+-*/%^(),", r):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	vars   []string
+	seen   map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) done() bool {
+	return p.peek().kind == tokenEOF
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		t := p.peek()
+		return fmt.Errorf("expected %q but got %q at position %d", op, t.text, t.pos)
+	}
+	p.next()
+	return nil
+}
+
+// expr := term (('+'|'-') term)*
+func (p *parser) parseExpr() (evalFunc, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(op, left, right)
+	}
+	return left, nil
+}
+
+// term := unary (('*'|'/'|'%') unary)*
+func (p *parser) parseTerm() (evalFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(op, left, right)
+	}
+	return left, nil
+}
+
+// unary := ('-'|'+') unary | power
+func (p *parser) parseUnary() (evalFunc, error) {
+	if p.isOperator("-", "+") {
+		op := p.next().text
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return operand, nil
+		}
+		return func(vars map[string]float64) (float64, error) {
+			v, err := operand(vars)
+			return -v, err
+		}, nil
+	}
+	return p.parsePower()
+}
+
+// power := primary ('^' unary)?, power is right associative so 2^3^2 is 2^9
+func (p *parser) parsePower() (evalFunc, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		p.next()
+		exp, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binary("^", base, exp), nil
+	}
+	return base, nil
+}
+
+// primary := number | constant | variable | function '(' args ')' | '(' expr ')'
+func (p *parser) parsePrimary() (evalFunc, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		v := t.number
+		return func(map[string]float64) (float64, error) { return v, nil }, nil
+	case tokenIdent:
+		if p.isOperator("(") {
+			return p.parseCall(t)
+		}
+		if v, ok := expressionConstants[t.text]; ok {
+			return func(map[string]float64) (float64, error) { return v, nil }, nil
+		}
+		name := t.text
+		if !p.seen[name] {
+			p.seen[name] = true
+			p.vars = append(p.vars, name)
+		}
+		return func(vars map[string]float64) (float64, error) {
+			v, ok := vars[name]
+			if !ok {
+				return 0, fmt.Errorf("no value for variable: %s", name)
+			}
+			return v, nil
+		}, nil
+	case tokenOperator:
+		if t.text == "(" {
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+func (p *parser) parseCall(name token) (evalFunc, error) {
+	f, ok := expressionFunctions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	p.next() // (
+	var args []evalFunc
+	if !p.isOperator(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) < f.minArgs || (f.maxArgs >= 0 && len(args) > f.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for %s: %d", name.text, len(args))
+	}
+	return func(vars map[string]float64) (float64, error) {
+		values := make([]float64, len(args))
+		for i, arg := range args {
+			v, err := arg(vars)
+			if err != nil {
+				return 0, err
+			}
+			values[i] = v
+		}
+		return f.call(values)
+	}, nil
+}
+
+func binary(op string, left, right evalFunc) evalFunc {
+	return func(vars map[string]float64) (float64, error) {
+		l, err := left(vars)
+		if err != nil {
+			return 0, err
+		}
+		r, err := right(vars)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			if r == 0 {
+				return 0, ErrDivideByZero
+			}
+			return l / r, nil
+		case "%":
+			return Mod(l, r)
+		default:
+			return Pow(l, r)
+		}
+	}
+}
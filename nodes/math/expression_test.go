@@ -0,0 +1,83 @@
+package math
+
+import (
+	"errors"
+	gomath "math"
+	"reflect"
+	"testing"
+)
+
+func TestExpression(t *testing.T) {
+	testCases := []struct {
+		expression     string
+		vars           map[string]float64
+		expectedResult float64
+	}{
+		{"(a*1.8)+32", map[string]float64{"a": 100}, 212},
+		{"a*1.8+32", map[string]float64{"a": -40}, -40},
+		{"1 + 2 * 3", nil, 7},
+		{"(1 + 2) * 3", nil, 9},
+		{"10 - 4 - 3", nil, 3},
+		{"2^3^2", nil, 512},
+		{"-2^2", nil, -4},
+		{"7 % 4", nil, 3},
+		{"1.5e2 / 3", nil, 50},
+		{"2 * pi", nil, 2 * gomath.Pi},
+		{"round(temp, 1)", map[string]float64{"temp": 21.46}, 21.5},
+		{"max(a, b, 3)", map[string]float64{"a": 1, "b": 2}, 3},
+		{"scale(ma, 4, 20, 0, 100)", map[string]float64{"ma": 20}, 100},
+		{"clamp(x, 0, 10) + abs(-1)", map[string]float64{"x": 50}, 11},
+		{"log(8, 2) + log10(100) + ln(e)", nil, 6},
+		{"sqrt(pow(3, 2) + pow(4, 2))", nil, 5},
+	}
+	for _, testCase := range testCases {
+		expr, err := Compile(testCase.expression)
+		if err != nil {
+			t.Errorf("Expression: %s, compile err: %v", testCase.expression, err)
+			continue
+		}
+		result, err := expr.Eval(testCase.vars)
+		if err != nil {
+			t.Errorf("Expression: %s, eval err: %v", testCase.expression, err)
+			continue
+		}
+		if gomath.Abs(result-testCase.expectedResult) > 1e-9 {
+			t.Errorf("Expression: %s, Vars: %v, Expected: %f, Got: %f", testCase.expression, testCase.vars, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestExpressionVars(t *testing.T) {
+	expr, err := Compile("b * sin(a) + b / c_1 + pi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars := expr.Vars(); !reflect.DeepEqual(vars, []string{"b", "a", "c_1"}) {
+		t.Errorf("unexpected vars: %v", vars)
+	}
+	if _, err := expr.Eval(map[string]float64{"a": 1, "b": 2}); err == nil {
+		t.Errorf("expected an error for the missing variable c_1")
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	for _, bad := range []string{"", "1 +", "(a * 2", "a b", "foo(1)", "sqrt(1, 2)", "1 $ 2", "clamp(1, 2)"} {
+		if _, err := Compile(bad); err == nil {
+			t.Errorf("Expression: %q, expected a compile error", bad)
+		}
+	}
+	expr, err := Compile("a / b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expr.Eval(map[string]float64{"a": 1, "b": 0}); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("expected ErrDivideByZero, got %v", err)
+	}
+	expr, err = Compile("sqrt(x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expr.Eval(map[string]float64{"x": -1}); err == nil {
+		t.Errorf("expected an error for sqrt of a negative number")
+	}
+}
@@ -0,0 +1,133 @@
+package math
+
+import (
+	"errors"
+	"fmt"
+	gomath "math"
+)
+
+var ErrNotANumber = errors.New("result is not a number")
+
+// checkResult turns NaN and Inf results into an error so they are not sent downstream
+func checkResult(v float64) (float64, error) {
+	if gomath.IsNaN(v) || gomath.IsInf(v, 0) {
+		return 0, ErrNotANumber
+	}
+	return v, nil
+}
+
+func Pow(base, exp float64) (float64, error) {
+	return checkResult(gomath.Pow(base, exp))
+}
+
+func Sqrt(x float64) (float64, error) {
+	if x < 0 {
+		return 0, fmt.Errorf("sqrt of a negative number: %v", x)
+	}
+	return gomath.Sqrt(x), nil
+}
+
+// Mod returns the remainder of x/y with the sign of x
+func Mod(x, y float64) (float64, error) {
+	if y == 0 {
+		return 0, ErrDivideByZero
+	}
+	return gomath.Mod(x, y), nil
+}
+
+func Abs(x float64) float64 {
+	return gomath.Abs(x)
+}
+
+const (
+	RoundNearest = "round"
+	RoundFloor   = "floor"
+	RoundCeil    = "ceil"
+)
+
+// Round rounds x to a number of decimal places, negative decimals round to tens, hundreds...
+func Round(mode string, x float64, decimals int) (float64, error) {
+	scale := gomath.Pow(10, float64(decimals))
+	var f func(float64) float64
+	switch mode {
+	case RoundNearest, "":
+		f = gomath.Round
+	case RoundFloor:
+		f = gomath.Floor
+	case RoundCeil:
+		f = gomath.Ceil
+	default:
+		return 0, fmt.Errorf("invalid round mode: %s", mode)
+	}
+	return checkResult(f(x*scale) / scale)
+}
+
+// Clamp holds x between min and max
+func Clamp(x, min, max float64) (float64, error) {
+	if min > max {
+		return 0, fmt.Errorf("clamp min %v is greater than max %v", min, max)
+	}
+	return gomath.Max(min, gomath.Min(max, x)), nil
+}
+
+// Scale linearly maps x from the input range to the output range, eg; 4-20mA to 0-100%
+func Scale(x, inMin, inMax, outMin, outMax float64) (float64, error) {
+	if inMin == inMax {
+		return 0, errors.New("scale input min and max can not be the same")
+	}
+	return outMin + (x-inMin)*(outMax-outMin)/(inMax-inMin), nil
+}
+
+const (
+	Sin  = "sin"
+	Cos  = "cos"
+	Tan  = "tan"
+	Asin = "asin"
+	Acos = "acos"
+	Atan = "atan"
+)
+
+var trigFunctions = map[string]func(float64) float64{
+	Sin:  gomath.Sin,
+	Cos:  gomath.Cos,
+	Tan:  gomath.Tan,
+	Asin: gomath.Asin,
+	Acos: gomath.Acos,
+	Atan: gomath.Atan,
+}
+
+// Trig runs a trig function, if degrees is set the input of sin/cos/tan and output of asin/acos/atan are in degrees
+func Trig(function string, x float64, degrees bool) (float64, error) {
+	f, ok := trigFunctions[function]
+	if !ok {
+		return 0, fmt.Errorf("invalid trig function: %s", function)
+	}
+	inverse := function == Asin || function == Acos || function == Atan
+	if degrees && !inverse {
+		x = x * gomath.Pi / 180
+	}
+	result := f(x)
+	if degrees && inverse {
+		result = result * 180 / gomath.Pi
+	}
+	return checkResult(result)
+}
+
+// Log returns the log of x in base, a base of 0 is the natural log
+func Log(base, x float64) (float64, error) {
+	if x <= 0 {
+		return 0, fmt.Errorf("log of a number that is not positive: %v", x)
+	}
+	switch base {
+	case 0, gomath.E:
+		return gomath.Log(x), nil
+	case 10:
+		return gomath.Log10(x), nil
+	case 2:
+		return gomath.Log2(x), nil
+	}
+	if base < 0 || base == 1 {
+		return 0, fmt.Errorf("invalid log base: %v", base)
+	}
+	return gomath.Log(x) / gomath.Log(base), nil
+}
@@ -0,0 +1,55 @@
+package math
+
+import (
+	"errors"
+	gomath "math"
+	"testing"
+)
+
+func TestFunctions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		f              func() (float64, error)
+		expectedResult float64
+		expectErr      bool
+	}{
+		{"pow", func() (float64, error) { return Pow(2, 10) }, 1024, false},
+		{"pow nan", func() (float64, error) { return Pow(-8, 0.5) }, 0, true},
+		{"sqrt", func() (float64, error) { return Sqrt(81) }, 9, false},
+		{"sqrt negative", func() (float64, error) { return Sqrt(-1) }, 0, true},
+		{"mod", func() (float64, error) { return Mod(-7, 3) }, -1, false},
+		{"mod zero", func() (float64, error) { return Mod(7, 0) }, 0, true},
+		{"round", func() (float64, error) { return Round(RoundNearest, 21.456, 2) }, 21.46, false},
+		{"round tens", func() (float64, error) { return Round(RoundNearest, 1234, -1) }, 1230, false},
+		{"floor", func() (float64, error) { return Round(RoundFloor, 21.459, 1) }, 21.4, false},
+		{"ceil", func() (float64, error) { return Round(RoundCeil, 21.41, 1) }, 21.5, false},
+		{"round bad mode", func() (float64, error) { return Round("up", 1, 0) }, 0, true},
+		{"clamp high", func() (float64, error) { return Clamp(120, 0, 100) }, 100, false},
+		{"clamp low", func() (float64, error) { return Clamp(-5, 0, 100) }, 0, false},
+		{"clamp bad range", func() (float64, error) { return Clamp(5, 10, 0) }, 0, true},
+		{"scale 4-20mA", func() (float64, error) { return Scale(12, 4, 20, 0, 100) }, 50, false},
+		{"scale reversed", func() (float64, error) { return Scale(0, 0, 10, 100, 0) }, 100, false},
+		{"scale bad range", func() (float64, error) { return Scale(1, 5, 5, 0, 1) }, 0, true},
+		{"sin degrees", func() (float64, error) { return Trig(Sin, 90, true) }, 1, false},
+		{"atan degrees", func() (float64, error) { return Trig(Atan, 1, true) }, 45, false},
+		{"asin out of range", func() (float64, error) { return Trig(Asin, 2, false) }, 0, true},
+		{"log10", func() (float64, error) { return Log(10, 1000) }, 3, false},
+		{"log2", func() (float64, error) { return Log(2, 8) }, 3, false},
+		{"ln", func() (float64, error) { return Log(0, gomath.E) }, 1, false},
+		{"log base 3", func() (float64, error) { return Log(3, 81) }, 4, false},
+		{"log of zero", func() (float64, error) { return Log(10, 0) }, 0, true},
+		{"log base 1", func() (float64, error) { return Log(1, 10) }, 0, true},
+	}
+	for _, testCase := range testCases {
+		result, err := testCase.f()
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Function: %s, Err: %v", testCase.name, err)
+		}
+		if gomath.Abs(result-testCase.expectedResult) > 1e-9 {
+			t.Errorf("Function: %s, Expected: %f, Got: %f", testCase.name, testCase.expectedResult, result)
+		}
+	}
+	if _, err := Mod(1, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("expected ErrDivideByZero, got %v", err)
+	}
+}
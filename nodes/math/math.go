@@ -1,29 +1,59 @@
-package main
+package math
 
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Add      = "add"
+	Subtract = "subtract"
+	Multiply = "multiply"
+	Divide   = "divide"
+	Min      = "min"
+	Max      = "max"
+	Avg      = "avg"
+)
+
+var ErrDivideByZero = errors.New("divide by zero")
+
+// Define a map that associates each operation type with a function
+var operations = map[string]func([]float64) float64{
+	Add:      func(arr []float64) float64 { return sum(arr) },
+	Subtract: func(arr []float64) float64 { return subtract(arr) },
+	Multiply: func(arr []float64) float64 { return multiply(arr) },
+	Divide:   func(arr []float64) float64 { return divide(arr) },
+	Min:      func(arr []float64) float64 { return minimum(arr) },
+	Max:      func(arr []float64) float64 { return maximum(arr) },
+	Avg:      func(arr []float64) float64 { return avg(arr) },
+}
+
+// CalculateMathOperation returns 0 for any error, use Calculate to get the error
 func CalculateMathOperation(operation string, inputs []float64) float64 {
-	if len(inputs) == 0 {
-		// No input values provided, return 0 (or handle it as needed)
+	result, err := Calculate(operation, inputs)
+	if err != nil {
 		return 0
 	}
+	return result
+}
 
-	// Define a map that associates each operation type with a function
-	operations := map[string]func([]float64) float64{
-		"add":      func(arr []float64) float64 { return sum(arr) },
-		"subtract": func(arr []float64) float64 { return subtract(arr) },
-		"multiply": func(arr []float64) float64 { return multiply(arr) },
-		"divide":   func(arr []float64) float64 { return divide(arr) },
-		"min":      func(arr []float64) float64 { return minimum(arr) },
-		"max":      func(arr []float64) float64 { return maximum(arr) },
-		"avg":      func(arr []float64) float64 { return avg(arr) },
-	}
-
+// Calculate runs the operation over the inputs in order
+func Calculate(operation string, inputs []float64) (float64, error) {
 	operationFunc, exists := operations[operation]
 	if !exists {
-		// Invalid math operation, return 0 (or handle it as needed)
-		return 0
+		return 0, fmt.Errorf("invalid math operation: %s", operation)
 	}
-
-	return operationFunc(inputs)
+	if len(inputs) == 0 {
+		return 0, errors.New("no input values provided")
+	}
+	if operation == Divide {
+		for _, v := range inputs[1:] {
+			if v == 0 {
+				return 0, ErrDivideByZero
+			}
+		}
+	}
+	return operationFunc(inputs), nil
 }
 
 func sum(arr []float64) float64 {
@@ -1,6 +1,7 @@
-package main
+package math
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -33,3 +34,30 @@ func TestCalculateMathOperation(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculate(t *testing.T) {
+	testCases := []struct {
+		operation      string
+		inputs         []float64
+		expectedResult float64
+		expectErr      bool
+	}{
+		{Divide, []float64{10, 4}, 2.5, false},
+		{Divide, []float64{10, 0}, 0, true},
+		{Divide, []float64{0, 10}, 0, false},
+		{"invalid", []float64{1}, 0, true},
+		{Add, nil, 0, true},
+	}
+	for _, testCase := range testCases {
+		result, err := Calculate(testCase.operation, testCase.inputs)
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Operation: %s, Inputs: %v, Err: %v", testCase.operation, testCase.inputs, err)
+		}
+		if result != testCase.expectedResult {
+			t.Errorf("Operation: %s, Inputs: %v, Expected: %f, Got: %f", testCase.operation, testCase.inputs, testCase.expectedResult, result)
+		}
+	}
+	if _, err := Calculate(Divide, []float64{1, 0}); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("expected ErrDivideByZero, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package netprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Result is the outcome of a single probe
+type Result struct {
+	Reachable bool
+	Latency   time.Duration
+	Err       error
+}
+
+// Prober runs a single reachability check against a target
+type Prober interface {
+	Probe(ctx context.Context) Result
+}
+
+// NewTCP creates a prober that checks a TCP port can be connected to, eg; 192.168.15.10:502
+func NewTCP(address string, timeout time.Duration) (Prober, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("invalid tcp address %s: %w", address, err)
+	}
+	return &tcpProbe{address: address, timeout: timeout}, nil
+}
+
+type tcpProbe struct {
+	address string
+	timeout time.Duration
+}
+
+func (p *tcpProbe) Probe(ctx context.Context) Result {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+	var d net.Dialer
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return Result{Err: err}
+	}
+	latency := time.Since(start)
+	conn.Close()
+	return Result{Reachable: true, Latency: latency}
+}
+
+// NewDNS creates a prober that resolves a host name
+// if server is not empty the lookup is sent to that dns server (host:port) instead of the system resolver
+func NewDNS(host, server string, timeout time.Duration) (Prober, error) {
+	if host == "" {
+		return nil, errors.New("dns host can not be empty")
+	}
+	resolver := net.DefaultResolver
+	if server != "" {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			return nil, fmt.Errorf("invalid dns server %s: %w", server, err)
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	return &dnsProbe{host: host, resolver: resolver, timeout: timeout}, nil
+}
+
+type dnsProbe struct {
+	host     string
+	resolver *net.Resolver
+	timeout  time.Duration
+}
+
+func (p *dnsProbe) Probe(ctx context.Context) Result {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+	start := time.Now()
+	addrs, err := p.resolver.LookupHost(ctx, p.host)
+	if err != nil {
+		return Result{Err: err}
+	}
+	if len(addrs) == 0 {
+		return Result{Err: fmt.Errorf("no addresses found for host: %s", p.host)}
+	}
+	return Result{Reachable: true, Latency: time.Since(start)}
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
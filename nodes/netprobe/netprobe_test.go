@@ -0,0 +1,129 @@
+package netprobe
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	p, err := NewTCP(addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := p.Probe(context.Background())
+	if !r.Reachable {
+		t.Errorf("expected %s to be reachable, err: %v", addr, r.Err)
+	}
+
+	l.Close()
+	r = p.Probe(context.Background())
+	if r.Reachable {
+		t.Errorf("expected %s to be unreachable after close", addr)
+	}
+
+	if _, err := NewTCP("localhost", time.Second); err == nil {
+		t.Errorf("expected an error for an address without a port")
+	}
+}
+
+func TestDNS(t *testing.T) {
+	p, err := NewDNS("localhost", "", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := p.Probe(context.Background())
+	if !r.Reachable {
+		t.Errorf("expected localhost to resolve, err: %v", r.Err)
+	}
+
+	p, err = NewDNS("does-not-exist.invalid", "", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = p.Probe(context.Background())
+	if r.Reachable {
+		t.Errorf("expected .invalid host to fail")
+	}
+}
+
+func TestPing(t *testing.T) {
+	p, err := NewPing("127.0.0.1", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := p.Probe(context.Background())
+	if r.Err != nil && !r.Reachable {
+		t.Skipf("icmp not permitted in this environment: %v", r.Err)
+	}
+	if !r.Reachable {
+		t.Errorf("expected localhost to reply to ping")
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := NewStats(4)
+	s.Add(Result{Reachable: true, Latency: 10 * time.Millisecond})
+	s.Add(Result{Reachable: false})
+	s.Add(Result{Reachable: true, Latency: 20 * time.Millisecond})
+	s.Add(Result{Reachable: false})
+	if got := s.LossPercent(); got != 50 {
+		t.Errorf("LossPercent() = %v, want 50", got)
+	}
+	if got := s.AvgLatency(); got != 15*time.Millisecond {
+		t.Errorf("AvgLatency() = %v, want 15ms", got)
+	}
+	// window is full so the first result is dropped
+	s.Add(Result{Reachable: false})
+	if got := s.LossPercent(); got != 75 {
+		t.Errorf("LossPercent() = %v, want 75", got)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	b := echoPacket(icmpv4EchoRequest, 1, 1, []byte("abc"))
+	if checksum(b) != 0 {
+		t.Errorf("checksum over a packet with its checksum set should be 0")
+	}
+}
+
+func TestStripIPv4Header(t *testing.T) {
+	reply := echoPacket(icmpv4EchoReply, 1, 2, nil)
+	header := make([]byte, 24)
+	header[0] = 0x46 // version 4, 6 words with options
+	tests := []struct {
+		packet   []byte
+		expected int // length left, -1 for nil
+	}{
+		{append(header[:20:20], reply...), len(reply)},
+		{append(header, reply...), len(reply)},
+		{reply, -1},       // no header
+		{header[:10], -1}, // short
+	}
+	tests[0].packet[0] = 0x45
+	for i, test := range tests {
+		got := stripIPv4Header(test.packet)
+		if (test.expected == -1 && got != nil) || (test.expected >= 0 && len(got) != test.expected) {
+			t.Errorf("packet %d Expected: %d bytes, Got: %v", i, test.expected, got)
+		}
+		if got != nil && got[0] != icmpv4EchoReply {
+			t.Errorf("packet %d Expected: an echo reply, Got: type %d", i, got[0])
+		}
+	}
+}
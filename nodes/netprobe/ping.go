@@ -0,0 +1,159 @@
+package netprobe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	icmpv4EchoRequest = 8
+	icmpv4EchoReply   = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+)
+
+var pingSeq uint32
+
+// NewPing creates an ICMP echo prober
+// a raw icmp socket is tried first, if the process is not privileged it will fall back to an unprivileged udp icmp socket (linux: net.ipv4.ping_group_range)
+func NewPing(host string, timeout time.Duration) (Prober, error) {
+	if host == "" {
+		return nil, errors.New("ping host can not be empty")
+	}
+	return &pingProbe{host: host, timeout: timeout, id: uint16(os.Getpid() & 0xffff)}, nil
+}
+
+type pingProbe struct {
+	host    string
+	timeout time.Duration
+	id      uint16
+}
+
+func (p *pingProbe) Probe(ctx context.Context) Result {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	ipAddr, err := net.DefaultResolver.LookupIPAddr(ctx, p.host)
+	if err != nil {
+		return Result{Err: err}
+	}
+	if len(ipAddr) == 0 {
+		return Result{Err: fmt.Errorf("no addresses found for host: %s", p.host)}
+	}
+	ip := ipAddr[0].IP
+	isV4 := ip.To4() != nil
+
+	conn, privileged, err := listenICMP(isV4)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	seq := uint16(atomic.AddUint32(&pingSeq, 1))
+	requestType, replyType := byte(icmpv4EchoRequest), byte(icmpv4EchoReply)
+	if !isV4 {
+		requestType, replyType = icmpv6EchoRequest, icmpv6EchoReply
+	}
+	packet := echoPacket(requestType, p.id, seq, []byte("reactive-nodes"))
+
+	var dst net.Addr = &net.IPAddr{IP: ip}
+	if !privileged {
+		dst = &net.UDPAddr{IP: ip}
+	}
+	start := time.Now()
+	if _, err := conn.WriteTo(packet, dst); err != nil {
+		return Result{Err: err}
+	}
+
+	// darwin includes the ip header on unprivileged icmpv4 reads, raw sockets and linux give the icmp message
+	withHeader := isV4 && !privileged && runtime.GOOS == "darwin"
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return Result{Err: err}
+		}
+		reply := buf[:n]
+		if withHeader {
+			reply = stripIPv4Header(reply)
+		}
+		if len(reply) < 8 || reply[0] != replyType {
+			continue
+		}
+		// the kernel rewrites the id on unprivileged sockets so only the sequence can be matched
+		if privileged && binary.BigEndian.Uint16(reply[4:6]) != p.id {
+			continue
+		}
+		if binary.BigEndian.Uint16(reply[6:8]) != seq {
+			continue
+		}
+		return Result{Reachable: true, Latency: time.Since(start)}
+	}
+}
+
+// stripIPv4Header returns the packet after the ipv4 header, the header length is ihl 32 bit words
+// nil is returned if the packet is not ipv4 or is shorter than its header
+func stripIPv4Header(b []byte) []byte {
+	if len(b) < 20 || b[0]>>4 != 4 {
+		return nil
+	}
+	ihl := int(b[0]&0x0f) * 4
+	if ihl < 20 || len(b) < ihl {
+		return nil
+	}
+	return b[ihl:]
+}
+
+// listenICMP opens a raw icmp socket and falls back to an unprivileged one
+func listenICMP(isV4 bool) (net.PacketConn, bool, error) {
+	raw, dgram, addr := "ip4:icmp", "udp4", "0.0.0.0"
+	if !isV4 {
+		raw, dgram, addr = "ip6:ipv6-icmp", "udp6", "::"
+	}
+	conn, err := net.ListenPacket(raw, addr)
+	if err == nil {
+		return conn, true, nil
+	}
+	conn, udpErr := listenUnprivilegedICMP(dgram, addr)
+	if udpErr != nil {
+		return nil, false, fmt.Errorf("failed to open icmp socket: %v, unprivileged fallback: %v", err, udpErr)
+	}
+	return conn, false, nil
+}
+
+// echoPacket builds an icmp echo request, the checksum is left to the kernel for icmpv6
+func echoPacket(requestType byte, id, seq uint16, payload []byte) []byte {
+	b := make([]byte, 8+len(payload))
+	b[0] = requestType
+	binary.BigEndian.PutUint16(b[4:6], id)
+	binary.BigEndian.PutUint16(b[6:8], seq)
+	copy(b[8:], payload)
+	if requestType == icmpv4EchoRequest {
+		binary.BigEndian.PutUint16(b[2:4], checksum(b))
+	}
+	return b
+}
+
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
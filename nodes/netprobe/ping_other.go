@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package netprobe
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+func listenUnprivilegedICMP(network, address string) (net.PacketConn, error) {
+	return nil, fmt.Errorf("unprivileged icmp is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,68 @@
+package netprobe
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats keeps a rolling window of probe results
+type Stats struct {
+	mu      sync.Mutex
+	window  int
+	results []Result
+}
+
+// NewStats creates a rolling window over the last n results, n is forced to a min of 1
+func NewStats(window int) *Stats {
+	if window < 1 {
+		window = 1
+	}
+	return &Stats{
+		window:  window,
+		results: make([]Result, 0, window),
+	}
+}
+
+// Add adds a result and drops the oldest once the window is full
+func (s *Stats) Add(r Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.results) == s.window {
+		s.results = s.results[1:]
+	}
+	s.results = append(s.results, r)
+}
+
+// LossPercent returns the percentage of failed probes in the window
+func (s *Stats) LossPercent() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.results) == 0 {
+		return 0
+	}
+	var lost int
+	for _, r := range s.results {
+		if !r.Reachable {
+			lost++
+		}
+	}
+	return float64(lost) / float64(len(s.results)) * 100
+}
+
+// AvgLatency returns the average latency of the successful probes in the window
+func (s *Stats) AvgLatency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total time.Duration
+	var count int
+	for _, r := range s.results {
+		if r.Reachable {
+			total += r.Latency
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
@@ -0,0 +1,237 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CronEntry turns the schedule on when On fires and off when Off fires
+type CronEntry struct {
+	On  string `json:"on"`
+	Off string `json:"off"`
+}
+
+// Period is a weekly on period, eg; mon-fri 08:00 to 17:00
+// if End is before Start the period runs over midnight into the next day
+type Period struct {
+	Days  []string `json:"days"`  // sun, mon, tue, wed, thu, fri, sat
+	Start string   `json:"start"` // HH:MM
+	End   string   `json:"end"`   // HH:MM
+}
+
+// Config is everything needed to build a Schedule
+type Config struct {
+	Timezone string      `json:"timezone"` // IANA name eg; Australia/Sydney, empty is UTC
+	Cron     []CronEntry `json:"cron"`
+	Weekly   []Period    `json:"weekly"`
+	Holidays []string    `json:"holidays"` // YYYY-MM-DD, the schedule is off for the whole day
+}
+
+type cronPair struct {
+	on  *Cron
+	off *Cron
+}
+
+type period struct {
+	days  [7]bool
+	start time.Duration // from midnight
+	end   time.Duration
+}
+
+// Schedule works out if it is on for a given time, all evaluation is done in the schedule timezone
+type Schedule struct {
+	loc      *time.Location
+	cron     []cronPair
+	weekly   []period
+	holidays map[string]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// New builds a schedule from the config, all errors in the config are returned together
+func New(config *Config) (*Schedule, error) {
+	if config == nil {
+		return nil, errors.New("schedule config can not be empty")
+	}
+	var errs []error
+	s := &Schedule{
+		loc:      time.UTC,
+		holidays: make(map[string]bool),
+	}
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid timezone: %w", err))
+		} else {
+			s.loc = loc
+		}
+	}
+	for _, entry := range config.Cron {
+		on, err := ParseCron(entry.On)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		off, err := ParseCron(entry.Off)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.cron = append(s.cron, cronPair{on: on, off: off})
+	}
+	for _, p := range config.Weekly {
+		parsed, err := parsePeriod(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.weekly = append(s.weekly, parsed)
+	}
+	for _, day := range config.Holidays {
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			errs = append(errs, fmt.Errorf("invalid holiday date %q, must be YYYY-MM-DD", day))
+			continue
+		}
+		s.holidays[day] = true
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return s, nil
+}
+
+func parsePeriod(p Period) (period, error) {
+	out := period{}
+	if len(p.Days) == 0 {
+		return out, errors.New("weekly period must have at least one day")
+	}
+	for _, day := range p.Days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return out, fmt.Errorf("invalid day %q in weekly period", day)
+		}
+		out.days[weekday] = true
+	}
+	var err error
+	if out.start, err = parseClock(p.Start); err != nil {
+		return out, err
+	}
+	if out.end, err = parseClock(p.End); err != nil {
+		return out, err
+	}
+	if out.start == out.end {
+		return out, fmt.Errorf("weekly period start and end can not be the same: %s", p.Start)
+	}
+	return out, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, must be HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Location returns the timezone the schedule is evaluated in
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// IsHoliday returns true if t falls on a holiday date
+func (s *Schedule) IsHoliday(t time.Time) bool {
+	return s.holidays[t.In(s.loc).Format(time.DateOnly)]
+}
+
+// State returns true if the schedule is on at t
+func (s *Schedule) State(t time.Time) bool {
+	t = t.In(s.loc)
+	if s.IsHoliday(t) {
+		return false
+	}
+	for _, p := range s.weekly {
+		if p.active(t) {
+			return true
+		}
+	}
+	for _, c := range s.cron {
+		lastOn := c.on.Prev(t)
+		if lastOn.IsZero() {
+			continue
+		}
+		if lastOn.After(c.off.Prev(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p period) active(t time.Time) bool {
+	// wall clock time so days with a daylight saving change still line up
+	sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if p.start < p.end {
+		return p.days[t.Weekday()] && sinceMidnight >= p.start && sinceMidnight < p.end
+	}
+	// over midnight, on from start today or until end if it started yesterday
+	yesterday := (t.Weekday() + 6) % 7
+	return (p.days[t.Weekday()] && sinceMidnight >= p.start) || (p.days[yesterday] && sinceMidnight < p.end)
+}
+
+// NextTransition returns the next time after t that the state changes and what it changes to
+// ok is false if there is no change found within the search limit
+func (s *Schedule) NextTransition(t time.Time) (next time.Time, state bool, ok bool) {
+	t = t.In(s.loc)
+	current := s.State(t)
+	limit := t.Add(searchLimit)
+	candidate := t
+	for {
+		candidate = s.nextCandidate(candidate)
+		if candidate.IsZero() || candidate.After(limit) {
+			return time.Time{}, current, false
+		}
+		if s.State(candidate) != current {
+			return candidate, !current, true
+		}
+	}
+}
+
+// nextCandidate returns the next time after t that the state could change
+func (s *Schedule) nextCandidate(t time.Time) time.Time {
+	var out time.Time
+	consider := func(c time.Time) {
+		if c.IsZero() || !c.After(t) {
+			return
+		}
+		if out.IsZero() || c.Before(out) {
+			out = c
+		}
+	}
+	for _, c := range s.cron {
+		consider(c.on.Next(t))
+		consider(c.off.Next(t))
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for _, p := range s.weekly {
+		for _, offset := range []time.Duration{p.start, p.end} {
+			c := atClock(midnight, offset)
+			if !c.After(t) {
+				c = atClock(midnight.AddDate(0, 0, 1), offset)
+			}
+			consider(c)
+		}
+	}
+	if len(s.holidays) > 0 {
+		consider(midnight.AddDate(0, 0, 1))
+	}
+	return out
+}
+
+// atClock returns the wall clock time on the day of midnight, time.Date is used so daylight saving changes are handled
+func atClock(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseCron(t *testing.T) {
+	testCases := []struct {
+		expr  string
+		valid bool
+	}{
+		{"0 8 * * 1-5", true},
+		{"*/15 * * * *", true},
+		{"30 6 1,15 jan-jun mon", true},
+		{"0 0 * * 7", true},
+		{"0 8 * *", false},
+		{"60 8 * * *", false},
+		{"0 8 * * 5-1", false},
+		{"*/0 8 * * *", false},
+	}
+	for _, testCase := range testCases {
+		_, err := ParseCron(testCase.expr)
+		if (err == nil) != testCase.valid {
+			t.Errorf("Expr: %q, Valid: %v, Err: %v", testCase.expr, testCase.valid, err)
+		}
+	}
+}
+
+func TestCronNextPrev(t *testing.T) {
+	c, err := ParseCron("0 8 * * mon-fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-03-08 is a friday
+	friday := date("2024-03-08 09:30")
+	if got, want := c.Next(friday), date("2024-03-11 08:00"); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+	if got, want := c.Prev(friday), date("2024-03-08 08:00"); !got.Equal(want) {
+		t.Errorf("Prev() = %v, want %v", got, want)
+	}
+	// prev is inclusive, next is not
+	at := date("2024-03-08 08:00")
+	if got := c.Prev(at); !got.Equal(at) {
+		t.Errorf("Prev() = %v, want %v", got, at)
+	}
+	if got, want := c.Next(at), date("2024-03-11 08:00"); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+
+	// restricted day-of-month and day-of-week match on either
+	c, _ = ParseCron("0 0 13 * fri")
+	if got, want := c.Next(date("2024-03-09 00:00")), date("2024-03-13 00:00"); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestScheduleWeekly(t *testing.T) {
+	s, err := New(&Config{
+		Weekly: []Period{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "08:00", End: "17:00"},
+			{Days: []string{"sat"}, Start: "22:00", End: "02:00"},
+		},
+		Holidays: []string{"2024-03-12"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		at       string
+		expected bool
+	}{
+		{"2024-03-11 07:59", false},
+		{"2024-03-11 08:00", true},
+		{"2024-03-11 16:59", true},
+		{"2024-03-11 17:00", false},
+		{"2024-03-12 10:00", false}, // holiday
+		{"2024-03-09 12:00", false},
+		{"2024-03-09 23:00", true}, // saturday night
+		{"2024-03-10 01:00", true}, // into sunday morning
+		{"2024-03-10 02:00", false},
+	}
+	for _, testCase := range testCases {
+		if result := s.State(date(testCase.at)); result != testCase.expected {
+			t.Errorf("At: %s, Expected: %v, Got: %v", testCase.at, testCase.expected, result)
+		}
+	}
+}
+
+func TestScheduleCron(t *testing.T) {
+	s, err := New(&Config{
+		Cron: []CronEntry{{On: "30 7 * * *", Off: "0 18 * * *"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(date("2024-03-11 07:00"))
+	if s.State(clk.Now()) {
+		t.Errorf("expected off before the on time")
+	}
+	next, state, ok := s.NextTransition(clk.Now())
+	if !ok || !state || !next.Equal(date("2024-03-11 07:30")) {
+		t.Errorf("NextTransition() = %v %v %v", next, state, ok)
+	}
+	clk.Set(date("2024-03-11 12:00"))
+	if !s.State(clk.Now()) {
+		t.Errorf("expected on after the on time")
+	}
+	next, state, ok = s.NextTransition(clk.Now())
+	if !ok || state || !next.Equal(date("2024-03-11 18:00")) {
+		t.Errorf("NextTransition() = %v %v %v", next, state, ok)
+	}
+}
+
+func TestScheduleTimezone(t *testing.T) {
+	s, err := New(&Config{
+		Timezone: "Australia/Sydney",
+		Weekly:   []Period{{Days: []string{"mon"}, Start: "09:00", End: "10:00"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-03-10 22:30 UTC is monday 09:30 in sydney (UTC+11)
+	if !s.State(date("2024-03-10 22:30")) {
+		t.Errorf("expected on in the schedule timezone")
+	}
+	if s.State(date("2024-03-11 09:30")) {
+		t.Errorf("expected off, 09:30 UTC is evening in sydney")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New(&Config{
+		Timezone: "Not/AZone",
+		Cron:     []CronEntry{{On: "bad", Off: "0 18 * * *"}},
+		Weekly:   []Period{{Days: []string{"funday"}, Start: "08:00", End: "09:00"}},
+		Holidays: []string{"25/12/2024"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -1,125 +1,143 @@
 package stream
 
-//
-//type AnyBufferNode struct {
-//	*BaseNode
-//	mu            sync.Mutex
-//	messageQueue  []interface{}
-//	startTime     time.Time // Add startTime variable
-//	totalMessages int
-//}
-//
-//// NewAnyBufferNode creates a new AnyBufferNode with the given ID, name, EventBus, and Flow.
-//func NewAnyBufferNode(nodeUUID, name string, bus *EventBus) *AnyBufferNode {
-//	node := NewBaseNode("any-buffer", nodeUUID, name, bus)
-//	node.NewInputPort("input", "input", portTypeAny)
-//	node.NewOutputPort("output-1", "output-1", portTypeAny)
-//	node.NewOutputPort("output-2", "output-2", portTypeFloat)
-//	node.NewOutputPort("output-3", "output-3", portTypeAvgMessageCount)
-//	node.AddToNodesMap(nodeUUID, node)
-//	node.SetLoaded(true)
-//	node.SetHotFix()
-//
-//	return &AnyBufferNode{
-//		BaseNode:      node,
-//		messageQueue:  make([]interface{}, 0),
-//		totalMessages: 0,
-//	}
-//}
-//
-//func (n *AnyBufferNode) Start() {
-//	for _, sub := range n.Connections {
-//		go func(sub *Connection) {
-//			inputChannel, exists := n.Bus[sub.TargetUUID]
-//			if !exists {
-//				fmt.Printf("Input channel for target input %s does not exist\n", sub.TargetUUID)
-//				return
-//			}
-//
-//			for {
-//				select {
-//				case msg, ok := <-inputChannel:
-//					if !ok {
-//						return
-//					}
-//
-//					// Lock the mutex before accessing the buffer
-//					n.mu.Lock()
-//					n.messageQueue = append(n.messageQueue, msg.Port.Value)
-//					n.totalMessages++
-//
-//					if n.totalMessages > 10 {
-//						// Calculate the sum of valid numeric values
-//						sum := calculateSum(n.messageQueue)
-//
-//						// Calculate the average message count
-//						avgPerSec := float64(n.totalMessages) / time.Since(n.startTime).Seconds()
-//						avgPerMin := avgPerSec * 60
-//						avgPerHour := avgPerMin * 60
-//						avgPerDay := avgPerHour * 24
-//
-//						// Send values on the outputs
-//						sendValueOnOutput(n, "output-1", sum)
-//						sendValueOnOutput(n, "output-2", float64(n.totalMessages))
-//						sendValueOnOutput(n, "output-3", AvgMessageCount{
-//							PerSec:  avgPerSec,
-//							PerMin:  avgPerMin,
-//							PerHour: avgPerHour,
-//							PerDay:  avgPerDay,
-//						})
-//					}
-//					// Unlock the mutex after updating the buffer
-//					n.mu.Unlock()
-//				}
-//			}
-//		}(sub)
-//	}
-//}
-//
-//// AvgMessageCount represents the average message counts per time interval.
-//type AvgMessageCount struct {
-//	PerSec  float64 `json:"perSec"`
-//	PerMin  float64 `json:"perMin"`
-//	PerHour float64 `json:"perHour"`
-//	PerDay  float64 `json:"perDay"`
-//}
-//
-//// CalculateAvgMessageCount calculates the average message counts based on the total messages and time duration.
-//func CalculateAvgMessageCount(totalMessages int, duration time.Duration) AvgMessageCount {
-//	perSec := float64(totalMessages) / duration.Seconds()
-//	perMin := perSec * 60
-//	perHour := perMin * 60
-//	perDay := perHour * 24
-//
-//	return AvgMessageCount{
-//		PerSec:  perSec,
-//		PerMin:  perMin,
-//		PerHour: perHour,
-//		PerDay:  perDay,
-//	}
-//}
-//
-//func calculateSum(values []interface{}) float64 {
-//	sum := 0.0
-//	for _, value := range values {
-//		// Check if the value is a valid numeric type (int or float)
-//		switch value.(type) {
-//		case int:
-//			sum += float64(value.(int))
-//		case float64:
-//			sum += value.(float64)
-//		}
-//	}
-//	return sum
-//}
-//
-//func sendValueOnOutput(n *AnyBufferNode, outputID string, value interface{}) {
-//	out := &Port{
-//		ID:        outputID,
-//		Name:      outputID,
-//		Value:     value,
-//		Direction: output,
-//		DataType:  portTypeAny,
-//	}
-//	n.PublishMessage(out, true)
-//}
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"math"
+	"sync"
+	"time"
+)
+
+// PortTypeAvgMessageCount is the port data type used when publishing an AvgMessageCount
+const PortTypeAvgMessageCount = "avg-message-count"
+
+// AvgMessageCount represents the average message counts per time interval.
+type AvgMessageCount struct {
+	PerSec  float64 `json:"perSec"`
+	PerMin  float64 `json:"perMin"`
+	PerHour float64 `json:"perHour"`
+	PerDay  float64 `json:"perDay"`
+}
+
+// CalculateAvgMessageCount calculates the average message counts based on the total messages and time duration.
+func CalculateAvgMessageCount(totalMessages int, duration time.Duration) AvgMessageCount {
+	if duration <= 0 {
+		return AvgMessageCount{}
+	}
+	perSec := float64(totalMessages) / duration.Seconds()
+	perMin := perSec * 60
+	perHour := perMin * 60
+	perDay := perHour * 24
+
+	return AvgMessageCount{
+		PerSec:  perSec,
+		PerMin:  perMin,
+		PerHour: perHour,
+		PerDay:  perDay,
+	}
+}
+
+// Stats are the statistics over the messages in a window
+// Count and Rate include every message, the other values only use the numeric messages
+type Stats struct {
+	Count   int             `json:"count"`
+	Numeric int             `json:"numeric"`
+	Sum     float64         `json:"sum"`
+	Min     float64         `json:"min"`
+	Max     float64         `json:"max"`
+	Mean    float64         `json:"mean"`
+	StdDev  float64         `json:"stdDev"`
+	Rate    AvgMessageCount `json:"rate"`
+}
+
+type sample struct {
+	at      time.Time
+	value   float64
+	numeric bool
+}
+
+// Window is a sliding window of messages, limited by count, age or both
+type Window struct {
+	mu       sync.Mutex
+	maxCount int
+	maxAge   time.Duration
+	samples  []sample
+	dropped  time.Time // newest message dropped by the count limit
+}
+
+// NewWindow creates a window keeping at most maxCount messages no older than maxAge, a zero value disables that limit
+func NewWindow(maxCount int, maxAge time.Duration) *Window {
+	return &Window{maxCount: maxCount, maxAge: maxAge}
+}
+
+// Add adds a message to the window, values that can not be converted to a number are only counted
+func (w *Window) Add(now time.Time, value any) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	v, err := convert.ToFloat(value)
+	w.samples = append(w.samples, sample{at: now, value: v, numeric: err == nil})
+	w.trim(now)
+}
+
+func (w *Window) trim(now time.Time) {
+	drop := 0
+	if w.maxCount > 0 && len(w.samples) > w.maxCount {
+		drop = len(w.samples) - w.maxCount
+		w.dropped = w.samples[drop-1].at
+	}
+	if w.maxAge > 0 {
+		for drop < len(w.samples) && now.Sub(w.samples[drop].at) > w.maxAge {
+			drop++
+		}
+	}
+	if drop > 0 {
+		w.samples = append(w.samples[:0], w.samples[drop:]...)
+	}
+}
+
+// Stats returns the statistics for the messages in the window at now
+// the rate is over the window age if one is set and the count limit has not dropped a message within that age,
+// otherwise over the time since the oldest message
+func (w *Window) Stats(now time.Time) Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.trim(now)
+	out := Stats{Count: len(w.samples)}
+	if out.Count == 0 {
+		return out
+	}
+	for _, s := range w.samples {
+		if !s.numeric {
+			continue
+		}
+		if out.Numeric == 0 || s.value < out.Min {
+			out.Min = s.value
+		}
+		if out.Numeric == 0 || s.value > out.Max {
+			out.Max = s.value
+		}
+		out.Sum += s.value
+		out.Numeric++
+	}
+	if out.Numeric > 0 {
+		out.Mean = out.Sum / float64(out.Numeric)
+		var variance float64
+		for _, s := range w.samples {
+			if s.numeric {
+				variance += (s.value - out.Mean) * (s.value - out.Mean)
+			}
+		}
+		out.StdDev = math.Sqrt(variance / float64(out.Numeric))
+	}
+	span := w.maxAge
+	if span <= 0 || w.countBound(now) {
+		span = now.Sub(w.samples[0].at)
+	}
+	out.Rate = CalculateAvgMessageCount(out.Count, span)
+	return out
+}
+
+// countBound is true when the count limit dropped a message that would still be inside the age limit, the window
+// then covers less time than the age
+func (w *Window) countBound(now time.Time) bool {
+	return !w.dropped.IsZero() && now.Sub(w.dropped) <= w.maxAge
+}
@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWindowCount(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := NewWindow(4, 0)
+	for i, v := range []any{100.0, 2, 4.0, "text", 6.0} {
+		w.Add(start.Add(time.Duration(i)*time.Second), v)
+	}
+	stats := w.Stats(start.Add(4 * time.Second))
+	// 100 has been pushed out of the window
+	if stats.Count != 4 || stats.Numeric != 3 {
+		t.Fatalf("Count: %d, Numeric: %d", stats.Count, stats.Numeric)
+	}
+	if stats.Sum != 12 || stats.Min != 2 || stats.Max != 6 || stats.Mean != 4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if math.Abs(stats.StdDev-math.Sqrt(8.0/3)) > 1e-9 {
+		t.Errorf("StdDev: %f", stats.StdDev)
+	}
+	// 4 messages over the 3 seconds since the oldest
+	if math.Abs(stats.Rate.PerSec-4.0/3) > 1e-9 {
+		t.Errorf("PerSec: %f", stats.Rate.PerSec)
+	}
+}
+
+func TestWindowAge(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := NewWindow(0, 10*time.Second)
+	for i := 0; i < 20; i++ {
+		w.Add(start.Add(time.Duration(i)*time.Second), float64(i))
+	}
+	stats := w.Stats(start.Add(19 * time.Second))
+	if stats.Count != 11 || stats.Min != 9 || stats.Max != 19 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Rate.PerSec != 1.1 || stats.Rate.PerMin != 66 {
+		t.Errorf("unexpected rate: %+v", stats.Rate)
+	}
+	// everything ages out
+	stats = w.Stats(start.Add(time.Minute))
+	if stats.Count != 0 || stats.Rate.PerSec != 0 {
+		t.Errorf("expected an empty window: %+v", stats)
+	}
+}
+
+func TestWindowCountAndAge(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := NewWindow(3, time.Minute)
+	// 10 messages a second, the count limit keeps the last 3
+	for i := 0; i < 10; i++ {
+		w.Add(start.Add(time.Duration(i)*100*time.Millisecond), i)
+	}
+	now := start.Add(900 * time.Millisecond)
+	stats := w.Stats(now)
+	if stats.Count != 3 {
+		t.Fatalf("Expected: 3, Got: %d", stats.Count)
+	}
+	// the rate is over the 200ms the 3 messages cover, not the minute
+	if math.Abs(stats.Rate.PerSec-15) > 0.001 {
+		t.Errorf("Expected: 15/s, Got: %v", stats.Rate.PerSec)
+	}
+
+	// once the dropped messages are older than the age the window is bound by age again
+	now = start.Add(61 * time.Second)
+	w.Add(now, 1)
+	stats = w.Stats(now)
+	if stats.Count != 1 || math.Abs(stats.Rate.PerMin-1) > 0.001 {
+		t.Errorf("Expected: 1 message at 1/min, Got: %d at %v/min", stats.Count, stats.Rate.PerMin)
+	}
+}
+
+func TestCalculateAvgMessageCount(t *testing.T) {
+	avg := CalculateAvgMessageCount(120, time.Minute)
+	if avg.PerSec != 2 || avg.PerMin != 120 || avg.PerHour != 7200 || avg.PerDay != 172800 {
+		t.Errorf("unexpected avg: %+v", avg)
+	}
+	if CalculateAvgMessageCount(10, 0) != (AvgMessageCount{}) {
+		t.Errorf("expected zero for no duration")
+	}
+}
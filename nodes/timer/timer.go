@@ -0,0 +1,146 @@
+package timer
+
+import "time"
+
+// Timer is a boolean state machine driven by an input and the current time
+// Update should be called on every input change and on a regular tick so time based changes are picked up
+type Timer interface {
+	Update(now time.Time, input bool) bool
+	Reset()
+}
+
+// OnDelay turns on once the input has been on for Delay, it turns off as soon as the input turns off
+type OnDelay struct {
+	Delay   time.Duration
+	onSince time.Time
+	output  bool
+}
+
+func NewOnDelay(delay time.Duration) *OnDelay {
+	return &OnDelay{Delay: delay}
+}
+
+func (t *OnDelay) Update(now time.Time, input bool) bool {
+	if !input {
+		t.onSince = time.Time{}
+		t.output = false
+		return t.output
+	}
+	if t.onSince.IsZero() {
+		t.onSince = now
+	}
+	t.output = now.Sub(t.onSince) >= t.Delay
+	return t.output
+}
+
+func (t *OnDelay) Reset() {
+	t.onSince = time.Time{}
+	t.output = false
+}
+
+// OffDelay turns on with the input and stays on for Delay after the input turns off
+type OffDelay struct {
+	Delay    time.Duration
+	offSince time.Time
+	output   bool
+}
+
+func NewOffDelay(delay time.Duration) *OffDelay {
+	return &OffDelay{Delay: delay}
+}
+
+func (t *OffDelay) Update(now time.Time, input bool) bool {
+	if input {
+		t.offSince = time.Time{}
+		t.output = true
+		return t.output
+	}
+	if !t.output {
+		return t.output
+	}
+	if t.offSince.IsZero() {
+		t.offSince = now
+	}
+	if now.Sub(t.offSince) >= t.Delay {
+		t.output = false
+		t.offSince = time.Time{}
+	}
+	return t.output
+}
+
+func (t *OffDelay) Reset() {
+	t.offSince = time.Time{}
+	t.output = false
+}
+
+// Pulse turns on for Width on a rising edge of the input, edges during the pulse are ignored
+// if Retrigger is set each rising edge restarts the pulse (a retriggerable monostable)
+type Pulse struct {
+	Width     time.Duration
+	Retrigger bool
+	lastInput bool
+	started   time.Time
+	output    bool
+}
+
+func NewPulse(width time.Duration) *Pulse {
+	return &Pulse{Width: width}
+}
+
+func NewMonostable(width time.Duration) *Pulse {
+	return &Pulse{Width: width, Retrigger: true}
+}
+
+func (t *Pulse) Update(now time.Time, input bool) bool {
+	rising := input && !t.lastInput
+	t.lastInput = input
+	if rising && (!t.output || t.Retrigger) {
+		t.started = now
+		t.output = true
+	}
+	if t.output && now.Sub(t.started) >= t.Width {
+		t.output = false
+	}
+	return t.output
+}
+
+func (t *Pulse) Reset() {
+	t.started = time.Time{}
+	t.output = false
+}
+
+// MinOnOff follows the input but once on stays on for at least MinOn, and once off stays off for at least MinOff
+// used for compressor protection, after a reset the output is off and is allowed to turn on straight away
+type MinOnOff struct {
+	MinOn   time.Duration
+	MinOff  time.Duration
+	changed time.Time
+	output  bool
+}
+
+func NewMinOnOff(minOn, minOff time.Duration) *MinOnOff {
+	return &MinOnOff{MinOn: minOn, MinOff: minOff}
+}
+
+func (t *MinOnOff) Update(now time.Time, input bool) bool {
+	if input == t.output {
+		return t.output
+	}
+	if !t.changed.IsZero() {
+		held := now.Sub(t.changed)
+		if t.output && held < t.MinOn {
+			return t.output
+		}
+		if !t.output && held < t.MinOff {
+			return t.output
+		}
+	}
+	t.output = input
+	t.changed = now
+	return t.output
+}
+
+func (t *MinOnOff) Reset() {
+	t.changed = time.Time{}
+	t.output = false
+}
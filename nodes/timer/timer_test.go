@@ -0,0 +1,93 @@
+package timer
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"testing"
+	"time"
+)
+
+type step struct {
+	advance  time.Duration
+	input    bool
+	expected bool
+}
+
+func runSteps(t *testing.T, name string, timer Timer, steps []step) {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	for i, s := range steps {
+		now := clk.Advance(s.advance)
+		if result := timer.Update(now, s.input); result != s.expected {
+			t.Errorf("%s step %d: Input: %v, Expected: %v, Got: %v", name, i, s.input, s.expected, result)
+		}
+	}
+}
+
+func TestOnDelay(t *testing.T) {
+	runSteps(t, "on-delay", NewOnDelay(5*time.Second), []step{
+		{0, false, false},
+		{time.Second, true, false},
+		{4 * time.Second, true, false},
+		{time.Second, true, true},
+		{time.Second, false, false},
+		{time.Second, true, false}, // restarts the delay
+		{3 * time.Second, true, false},
+	})
+}
+
+func TestOffDelay(t *testing.T) {
+	runSteps(t, "off-delay", NewOffDelay(5*time.Second), []step{
+		{0, false, false},
+		{time.Second, true, true},
+		{time.Second, false, true},
+		{4 * time.Second, false, true},
+		{time.Second, false, false},
+		{time.Second, true, true},
+		{time.Second, false, true},
+		{time.Second, true, true}, // input back on cancels the delay
+		{10 * time.Second, true, true},
+	})
+}
+
+func TestPulse(t *testing.T) {
+	runSteps(t, "pulse", NewPulse(3*time.Second), []step{
+		{0, true, true},
+		{time.Second, false, true},
+		{time.Second, true, true}, // edge during the pulse is ignored
+		{time.Second, true, false},
+		{time.Second, false, false},
+		{time.Second, true, true},
+	})
+}
+
+func TestMonostable(t *testing.T) {
+	runSteps(t, "monostable", NewMonostable(3*time.Second), []step{
+		{0, true, true},
+		{time.Second, false, true},
+		{time.Second, true, true}, // edge restarts the pulse
+		{2 * time.Second, true, true},
+		{time.Second, true, false},
+	})
+}
+
+func TestMinOnOff(t *testing.T) {
+	runSteps(t, "min-on-off", NewMinOnOff(10*time.Second, 20*time.Second), []step{
+		{0, true, true}, // no min off after a reset
+		{time.Second, false, true},
+		{8 * time.Second, false, true},
+		{time.Second, false, false}, // min on of 10s reached
+		{time.Second, true, false},
+		{18 * time.Second, true, false},
+		{time.Second, true, true}, // min off of 20s reached
+	})
+}
+
+func TestReset(t *testing.T) {
+	now := time.Now()
+	d := NewOffDelay(time.Minute)
+	d.Update(now, true)
+	d.Reset()
+	if d.Update(now, false) {
+		t.Errorf("expected off after reset")
+	}
+}
@@ -1 +1,144 @@
-package main
+package trigger
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"reflect"
+)
+
+// RisingEdge is true for the one update where the input goes from false to true
+// the input starts as false so a first input of true is an edge
+type RisingEdge struct {
+	last bool
+}
+
+func (e *RisingEdge) Update(input bool) bool {
+	edge := input && !e.last
+	e.last = input
+	return edge
+}
+
+func (e *RisingEdge) Reset() {
+	e.last = false
+}
+
+// FallingEdge is true for the one update where the input goes from true to false
+type FallingEdge struct {
+	last bool
+}
+
+func (e *FallingEdge) Update(input bool) bool {
+	edge := !input && e.last
+	e.last = input
+	return edge
+}
+
+func (e *FallingEdge) Reset() {
+	e.last = false
+}
+
+// Latch holds its output after set until reset, ResetDominant picks which wins when both are on
+// set dominant is an SR latch and reset dominant is an RS latch
+type Latch struct {
+	ResetDominant bool
+	output        bool
+}
+
+// NewSR is a set dominant latch
+func NewSR() *Latch {
+	return &Latch{}
+}
+
+// NewRS is a reset dominant latch
+func NewRS() *Latch {
+	return &Latch{ResetDominant: true}
+}
+
+func (l *Latch) Update(set, reset bool) bool {
+	switch {
+	case set && reset:
+		l.output = !l.ResetDominant
+	case set:
+		l.output = true
+	case reset:
+		l.output = false
+	}
+	return l.output
+}
+
+func (l *Latch) Output() bool {
+	return l.output
+}
+
+// Toggle flips its output on each rising edge of the input
+type Toggle struct {
+	edge   RisingEdge
+	output bool
+}
+
+func (t *Toggle) Update(input bool) bool {
+	if t.edge.Update(input) {
+		t.output = !t.output
+	}
+	return t.output
+}
+
+func (t *Toggle) Output() bool {
+	return t.output
+}
+
+// Reset turns the output off, an input that is still on has to go off and on again to toggle
+func (t *Toggle) Reset() {
+	t.output = false
+}
+
+// ChangeOfValue only passes a value when it differs from the last value passed
+// numbers are different when they have moved by more than Tolerance, other values when they are not equal
+type ChangeOfValue struct {
+	Tolerance float64
+	last      any
+	passed    bool
+}
+
+func NewChangeOfValue(tolerance float64) *ChangeOfValue {
+	return &ChangeOfValue{Tolerance: tolerance}
+}
+
+// Update returns true if the value should be passed, the first value is always passed
+func (c *ChangeOfValue) Update(value any) bool {
+	if c.passed && !c.changed(value) {
+		return false
+	}
+	c.last = value
+	c.passed = true
+	return true
+}
+
+func (c *ChangeOfValue) changed(value any) bool {
+	_, lastIsBool := c.last.(bool)
+	_, isBool := value.(bool)
+	if !lastIsBool && !isBool {
+		last, errLast := convert.ToFloat(c.last)
+		v, err := convert.ToFloat(value)
+		if errLast == nil && err == nil {
+			diff := v - last
+			if diff < 0 {
+				diff = -diff
+			}
+			if c.Tolerance <= 0 {
+				return diff != 0
+			}
+			return diff > c.Tolerance
+		}
+	}
+	return !reflect.DeepEqual(c.last, value)
+}
+
+// Last returns the last value passed
+func (c *ChangeOfValue) Last() any {
+	return c.last
+}
+
+func (c *ChangeOfValue) Reset() {
+	c.last = nil
+	c.passed = false
+}
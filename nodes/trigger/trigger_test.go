@@ -0,0 +1,85 @@
+package trigger
+
+import "testing"
+
+func TestEdges(t *testing.T) {
+	inputs := []bool{true, true, false, false, true, false}
+	rising := []bool{true, false, false, false, true, false}
+	falling := []bool{false, false, true, false, false, true}
+	var r RisingEdge
+	var f FallingEdge
+	for i, input := range inputs {
+		if got := r.Update(input); got != rising[i] {
+			t.Errorf("RisingEdge Step: %d, Expected: %v, Got: %v", i, rising[i], got)
+		}
+		if got := f.Update(input); got != falling[i] {
+			t.Errorf("FallingEdge Step: %d, Expected: %v, Got: %v", i, falling[i], got)
+		}
+	}
+}
+
+func TestLatch(t *testing.T) {
+	testCases := []struct {
+		set, reset bool
+		sr, rs     bool
+	}{
+		{false, false, false, false},
+		{true, false, true, true},
+		{false, false, true, true},
+		{true, true, true, false},
+		{false, true, false, false},
+		{false, false, false, false},
+		{true, true, true, false},
+	}
+	sr, rs := NewSR(), NewRS()
+	for i, testCase := range testCases {
+		if got := sr.Update(testCase.set, testCase.reset); got != testCase.sr {
+			t.Errorf("SR Step: %d, Expected: %v, Got: %v", i, testCase.sr, got)
+		}
+		if got := rs.Update(testCase.set, testCase.reset); got != testCase.rs {
+			t.Errorf("RS Step: %d, Expected: %v, Got: %v", i, testCase.rs, got)
+		}
+	}
+}
+
+func TestToggle(t *testing.T) {
+	var toggle Toggle
+	inputs := []bool{true, true, false, true, false, false, true}
+	expected := []bool{true, true, true, false, false, false, true}
+	for i, input := range inputs {
+		if got := toggle.Update(input); got != expected[i] {
+			t.Errorf("Step: %d, Expected: %v, Got: %v", i, expected[i], got)
+		}
+	}
+	toggle.Reset()
+	if toggle.Update(true) {
+		t.Errorf("expected a held input to not toggle after a reset")
+	}
+}
+
+func TestChangeOfValue(t *testing.T) {
+	testCases := []struct {
+		tolerance float64
+		inputs    []any
+		expected  []bool
+	}{
+		{0, []any{1.0, 1.0, 1, 2.0, "2"}, []bool{true, false, false, true, false}},
+		{0.5, []any{20.0, 20.4, 20.6, 20.2, 21.2}, []bool{true, false, true, false, true}},
+		{0, []any{"a", "a", "b", nil, nil}, []bool{true, false, true, true, false}},
+		{0, []any{true, true, 1, false}, []bool{true, false, true, true}},
+	}
+	for _, testCase := range testCases {
+		c := NewChangeOfValue(testCase.tolerance)
+		for i, input := range testCase.inputs {
+			if got := c.Update(input); got != testCase.expected[i] {
+				t.Errorf("Tolerance: %v, Step: %d, Input: %v, Expected: %v, Got: %v", testCase.tolerance, i, input, testCase.expected[i], got)
+			}
+		}
+	}
+	c := NewChangeOfValue(1)
+	c.Update(10.0)
+	c.Update(10.5)
+	if c.Last() != 10.0 {
+		t.Errorf("expected the last passed value to be 10, got %v", c.Last())
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/convert"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/control"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"math"
+	"time"
+)
+
+var PID pidObject
+
+const pidName = "pid"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryControl, name: pidName, export: "PID", node: &PID})
+}
+
+var pidInputs = []*ports.Definition{
+	ports.Float(constants.ProcessValue),
+	ports.Float(constants.Setpoint),
+	ports.Bool(constants.Enable),
+	ports.Bool(constants.Auto),
+	ports.Float(constants.Manual),
+}
+
+type pidSettings struct {
+	control.Config
+	Interval      int      `json:"interval"`      // ms, the fixed sample interval
+	Setpoint      *float64 `json:"setpoint"`      // used until the setpoint input has a value
+	DisabledValue float64  `json:"disabledValue"` // output while disabled
+}
+
+var pidSchema = func() *schemas.Schema {
+	s := schemas.New("PID")
+	s.Number("kp", "Proportional gain", 1).AtLeast(0)
+	s.Number("ki", "Integral gain", 0).AtLeast(0)
+	s.Number("kd", "Derivative gain", 0).AtLeast(0)
+	s.Number("outMin", "Output min", 0)
+	s.Number("outMax", "Output max", 100)
+	s.Enum("action", "Action", control.ActionReverse, control.ActionReverse, control.ActionDirect).
+		Names("Reverse, eg; heating", "Direct, eg; cooling")
+	s.Integer("interval", "Interval (ms)", 1000).AtLeast(10).Describe("the fixed sample interval")
+	s.Number("setpoint", "Setpoint", 0).Optional().Describe("used until the setpoint input has a value")
+	s.Number("disabledValue", "Disabled value", 0).Describe("output while disabled")
+	return s
+}()
+
+func defaultPIDSettings() *pidSettings {
+	return &pidSettings{
+		Config: control.Config{
+			Kp:     1,
+			OutMin: 0,
+			OutMax: 100,
+			Action: control.ActionReverse,
+		},
+		Interval: 1000,
+	}
+}
+
+// pidObject runs a PID loop on the process value and setpoint every interval
+// the auto input switches between auto and manual, in manual the output follows the manual input, the latest manual
+// value is kept while in auto and used when switching to manual
+type pidObject struct {
+	rxlib.Object
+	settings *pidSettings
+	pid      *control.PID
+	output   *ports.Definition
+	pv       *float64
+	setpoint *float64
+	manual   *float64
+	enabled  bool
+	loaded   *loadedSettings
+	stop     chan struct{}
+}
+
+func NewPIDObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(pidName, objectUUID, name, pluginName), bus)
+	ports.AddInputs(object, pidInputs...)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryControl,
+	})
+	s := defaultPIDSettings()
+	loaded := loadSettings(object, pidSchema, settings, s)
+	s.Config.Interval = ms(s.Interval)
+	// the output is limited to the output range, the disabled value is allowed outside it
+	output := ports.Float(constants.Output).WithRange(math.Min(s.OutMin, s.DisabledValue), math.Max(s.OutMax, s.DisabledValue))
+	ports.AddOutputs(object, output)
+	pid, err := control.New(s.Config)
+	if err != nil {
+		object.AddValidationResult("settings", err.Error())
+	}
+	return &pidObject{
+		Object:   object,
+		settings: s,
+		pid:      pid,
+		output:   output,
+		setpoint: s.Setpoint,
+		enabled:  true,
+		loaded:   loaded,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (n *pidObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewPIDObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+func (n *pidObject) CallSchema() *schema.Generated {
+	return pidSchema.Generated()
+}
+
+// UpdateSettings checks the new settings, the loop is built on create so a change needs a restart
+func (n *pidObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *pidObject) Start() {
+	if n.Loaded() || n.pid == nil {
+		return
+	}
+	n.SetLoaded(true)
+	inputs := mergeInputs(n, []string{constants.ProcessValue, constants.Setpoint, constants.Enable, constants.Auto, constants.Manual}, n.stop)
+	go func() {
+		ticker := time.NewTicker(ms(n.settings.Interval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-n.stop:
+				return
+			case in := <-inputs:
+				n.handleInput(in)
+				in.done()
+			case <-ticker.C:
+				n.sample()
+			}
+		}
+	}()
+}
+
+func (n *pidObject) handleInput(in inputMessage) {
+	value := messageValue(in.message)
+	switch in.inputID {
+	case constants.Enable:
+		n.enabled = inputBool(n, in.inputID, value)
+		if !n.enabled {
+			n.pid.Reset()
+			publishOutput(n, n.output, n.settings.DisabledValue)
+		}
+	case constants.Auto:
+		if inputBool(n, in.inputID, value) {
+			n.pid.SetAuto()
+		} else if !n.pid.Manual() {
+			// hold the last auto output if no manual value has arrived yet
+			if n.manual != nil {
+				n.pid.SetManual(*n.manual)
+			} else {
+				n.pid.SetManual(n.pid.Output())
+			}
+		}
+	default:
+		v, err := convert.ToFloat(value)
+		if err != nil {
+			objectLog(n).Warn("input is not a number", "port", in.inputID, "value", value, "err", err)
+			return
+		}
+		switch in.inputID {
+		case constants.ProcessValue:
+			n.pv = &v
+		case constants.Setpoint:
+			n.setpoint = &v
+		case constants.Manual:
+			n.manual = &v
+			if n.pid.Manual() {
+				n.pid.SetManual(v)
+			}
+		}
+	}
+}
+
+// sample runs the loop once, in auto nothing is sent until the process value and setpoint are known, in manual the
+// manual output is sent without them
+func (n *pidObject) sample() {
+	if !n.enabled {
+		return
+	}
+	if n.pv == nil || n.setpoint == nil {
+		if n.pid.Manual() {
+			publishOutput(n, n.output, n.pid.Output())
+		}
+		return
+	}
+	publishOutput(n, n.output, n.pid.Update(*n.setpoint, *n.pv))
+}
+
+func (n *pidObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
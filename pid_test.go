@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"testing"
+	"time"
+)
+
+// nextPIDOutput reads outputs until the expected one, the loop keeps sending the old output until the input is handled
+func nextPIDOutput(t *testing.T, h *nodetest.Harness, expected float64) {
+	t.Helper()
+	var got any
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if got = h.Next(constants.Output); got == expected {
+			return
+		}
+	}
+	t.Fatalf("Expected: %v, Got: %v", expected, got)
+}
+
+func TestPIDManualWithoutProcessValue(t *testing.T) {
+	h := nodetest.New(t, &PID, map[string]any{"interval": 50}).Start()
+	// manual holds the last auto output until a manual value arrives
+	h.Send(constants.Auto, false)
+	nextPIDOutput(t, h, 0)
+	h.Send(constants.Manual, 40)
+	nextPIDOutput(t, h, 40)
+}
+
+func TestPIDManualSentInAuto(t *testing.T) {
+	h := nodetest.New(t, &PID, map[string]any{"interval": 50}).Start()
+	h.Send(constants.Manual, 25)
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	h.Send(constants.Auto, false)
+	nextPIDOutput(t, h, 25)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/rxlib"
+)
+
+// errorOutput is the output used by objects that report bad inputs and failed calculations
+var errorOutput = ports.String(constants.Error)
+
+// publishOutput checks the value against the port definition and publishes it
+// a value that does not fit the port is logged and counted as a publish error, each value is logged at debug
+func publishOutput(n rxlib.Object, port *ports.Definition, value any) {
+	log := objectLog(n)
+	out, err := port.Output(value)
+	if err != nil {
+		objectMetrics(n).PublishError()
+		log.Error("value does not fit the output", "port", port.ID, "value", value, "err", err)
+		return
+	}
+	if port == errorOutput && value != "" {
+		log.Warn("error output", "err", value)
+	}
+	log.Debug("publish", "port", out.ID, "value", out.Value)
+	objectMetrics(n).Out(out.ID)
+	n.PublishMessage(out, true)
+}
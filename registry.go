@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/NubeIO/reactive/plugins"
+	"github.com/NubeIO/rxlib"
+)
+
+// nodeConstructor is implemented by every exported node var, the host looks the var up by its export symbol and calls New
+type nodeConstructor interface {
+	New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object
+}
+
+// nodeRegistration describes one exported node type, each node file registers its types in an init func
+type nodeRegistration struct {
+	category string
+	name     string // object id, the same name the node passes to reactive.ObjectInfo
+	export   string // name of the exported var
+	parent   string // name of the parent object for child objects, empty for a top level object
+	node     nodeConstructor
+}
+
+var nodeRegistry []nodeRegistration
+
+func registerNode(r nodeRegistration) {
+	nodeRegistry = append(nodeRegistry, r)
+}
+
+// buildExport builds the plugin export from the registrations, categories are added in the given order and parents
+// before their children, every problem found is returned together and the valid objects are still added
+func buildExport(e *plugins.Export, categories []string, registrations []nodeRegistration) error {
+	var errs []error
+	knownCategories := make(map[string]bool)
+	for _, category := range categories {
+		if knownCategories[category] {
+			errs = append(errs, fmt.Errorf("category %s is listed more than once", category))
+			continue
+		}
+		knownCategories[category] = true
+		e.AddCategory(category)
+	}
+
+	names := make(map[string]bool)
+	exports := make(map[string]bool)
+	var pending []nodeRegistration
+	for _, r := range registrations {
+		switch {
+		case r.name == "":
+			errs = append(errs, fmt.Errorf("export %s has no object name", r.export))
+			continue
+		case r.export == "":
+			errs = append(errs, fmt.Errorf("object %s has no export symbol", r.name))
+			continue
+		case r.node == nil:
+			errs = append(errs, fmt.Errorf("object %s has no constructor", r.name))
+			continue
+		case !knownCategories[r.category]:
+			errs = append(errs, fmt.Errorf("object %s has an unknown category: %q", r.name, r.category))
+			continue
+		case names[r.name]:
+			errs = append(errs, fmt.Errorf("object %s is registered more than once", r.name))
+			continue
+		case exports[r.export]:
+			errs = append(errs, fmt.Errorf("export %s is registered more than once", r.export))
+			continue
+		}
+		names[r.name] = true
+		exports[r.export] = true
+		pending = append(pending, r)
+	}
+
+	// add the objects whose parent is already added until nothing changes, whatever is left has no parent
+	added := make(map[string]string) // name -> category
+	for len(pending) > 0 {
+		var next []nodeRegistration
+		for _, r := range pending {
+			var err error
+			if r.parent == "" {
+				err = e.AddObject(r.category, r.name, r.export)
+			} else if parentCategory, ok := added[r.parent]; !ok {
+				next = append(next, r)
+				continue
+			} else if parentCategory != r.category {
+				err = fmt.Errorf("parent %s is in category %s not %s", r.parent, parentCategory, r.category)
+			} else {
+				err = e.AddChildObject(r.category, r.parent, r.name, r.export)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("object %s: %w", r.name, err))
+				continue
+			}
+			added[r.name] = r.category
+		}
+		if len(next) == len(pending) {
+			for _, r := range next {
+				errs = append(errs, fmt.Errorf("object %s: parent %s is not registered", r.name, r.parent))
+			}
+			break
+		}
+		pending = next
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package rxcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Fake is an in-memory Client for tests, each route is answered by a handler and every request is recorded
+type Fake struct {
+	routes
+	mu       sync.Mutex
+	handlers map[string]FakeHandler
+	requests []FakeRequest
+	down     bool
+}
+
+// FakeHandler answers a request, the returned value is decoded into the caller's out the same as a server response
+type FakeHandler func(body any) (any, error)
+
+type FakeRequest struct {
+	Path string
+	Body any
+}
+
+func NewFake() *Fake {
+	f := &Fake{handlers: make(map[string]FakeHandler)}
+	f.routes = routes{send: f.send, timeout: 1}
+	return f
+}
+
+// Handle sets the handler of a route, a request on a route with no handler returns an error
+func (f *Fake) Handle(path string, handler FakeHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[path] = handler
+}
+
+// SetDown makes every request and health check fail with ErrDisconnected, to test reconnection
+func (f *Fake) SetDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+// Requests returns the requests made so far, including the failed ones
+func (f *Fake) Requests() []FakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeRequest(nil), f.requests...)
+}
+
+func (f *Fake) Send(path string, body, out any) error {
+	return f.send(path, body, out, f.routes.timeout)
+}
+
+func (f *Fake) send(path string, body, out any, _ int) error {
+	f.mu.Lock()
+	f.requests = append(f.requests, FakeRequest{Path: path, Body: body})
+	handler, ok := f.handlers[path]
+	down := f.down
+	f.mu.Unlock()
+	if down {
+		return ErrDisconnected
+	}
+	if !ok {
+		return fmt.Errorf("%s: no handler", path)
+	}
+	data, err := handler(body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if out == nil || data == nil {
+		return nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func (f *Fake) Health() error {
+	if !f.Connected() {
+		return ErrDisconnected
+	}
+	return nil
+}
+
+func (f *Fake) Connected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.down
+}
@@ -0,0 +1,54 @@
+package rxcli
+
+import (
+	"fmt"
+	"github.com/NubeIO/rxclient"
+)
+
+// routes implements rxclient.RxClient on top of a send func so the Manager and Fake share the rx server routes
+type routes struct {
+	send    func(path string, body, out any, timeout int) error
+	timeout int // seconds
+}
+
+func (r routes) IPValidation(ip string) *rxclient.ValidationResponse {
+	resp := &rxclient.ValidationResponse{}
+	if err := r.send("validation/ip", ip, resp, r.timeout); err != nil {
+		resp.ErrorMessage = fmt.Sprintf("error sending request: %v", err)
+		resp.IsError = true
+	}
+	return resp
+}
+
+func (r routes) UserAll() ([]*rxclient.User, error) {
+	var out []*rxclient.User
+	err := r.send("users/all", nil, &out, r.timeout)
+	return out, err
+}
+
+func (r routes) RunCommand(body *rxclient.CommandBody) (*rxclient.Response, error) {
+	resp := &rxclient.Response{}
+	err := r.send("cmd/run", body, resp, body.Timeout+1)
+	return resp, err
+}
+
+func (r routes) SystemdStatus(unit string, timeout int) (*rxclient.StatusResp, error) {
+	body := &rxclient.CommandBody{
+		Command: unit,
+		Timeout: timeout,
+	}
+	resp := &rxclient.StatusResp{}
+	err := r.send("cmd/systemctl/status", body, resp, timeout+1)
+	return resp, err
+}
+
+func (r routes) SystemdCommand(unit string, commandType rxclient.SystemCTLCommand, timeout int) (*rxclient.Response, error) {
+	body := &rxclient.CommandBody{
+		Command: unit,
+		Arg:     string(commandType),
+		Timeout: timeout,
+	}
+	resp := &rxclient.Response{}
+	err := r.send("cmd/systemctl/command", body, resp, timeout+1)
+	return resp, err
+}
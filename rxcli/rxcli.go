@@ -1,19 +1,296 @@
 package rxcli
 
 import (
+	"errors"
+	"fmt"
 	"github.com/NubeIO/rxclient"
+	"github.com/NubeIO/unixclient"
+	"net"
+	"os"
+	"sync"
+	"time"
 )
 
-var rxClient rxclient.RxClient
+var ErrDisconnected = errors.New("rx client is not connected")
+var ErrConfigured = errors.New("rx client is already in use, configure it before the first call to Default")
 
-func RxClient() (rxclient.RxClient, error) {
-	if rxClient == nil {
-		r, err := rxclient.New()
-		if err != nil {
-			return nil, err
-		}
-		rxClient = r
-		return rxClient, nil
+// Client is the rx client used by the nodes, a Manager for the real rx server or a Fake in tests
+type Client interface {
+	rxclient.RxClient
+	// Send makes a request on any route of the rx server and decodes the response data into out
+	Send(path string, body, out any) error
+	// Health returns nil if the rx server can be reached
+	Health() error
+	Connected() bool
+}
+
+// Config of the connection to the rx server
+type Config struct {
+	Socket         string        // unix socket the rx server listens on
+	Timeout        time.Duration // of a single request, the rx server works in whole seconds
+	HealthInterval time.Duration // how often the connection is checked and re-connected
+	RetryMin       time.Duration // wait after the first failed connect, doubled on each failure
+	RetryMax       time.Duration
+}
+
+const defaultSocket = "/tmp/rx-server.sock"
+
+// DefaultConfig uses the socket in RX_SOCKET, or the default rx server socket
+func DefaultConfig() Config {
+	socket := os.Getenv("RX_SOCKET")
+	if socket == "" {
+		socket = defaultSocket
+	}
+	return Config{
+		Socket:         socket,
+		Timeout:        5 * time.Second,
+		HealthInterval: 10 * time.Second,
+		RetryMin:       time.Second,
+		RetryMax:       time.Minute,
+	}
+}
+
+var (
+	defaultMu      sync.Mutex
+	defaultOnce    sync.Once
+	defaultConfig  = DefaultConfig()
+	defaultManager *Manager
+)
+
+// Configure sets the config of the shared client, it must be called before the first call to Default
+func Configure(config Config) error {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultManager != nil {
+		return ErrConfigured
+	}
+	defaultConfig = config
+	return nil
+}
+
+// Default returns the client shared by all nodes, it is created and its health checks are started on the first call
+func Default() *Manager {
+	defaultOnce.Do(func() {
+		defaultMu.Lock()
+		defer defaultMu.Unlock()
+		defaultManager = NewManager(defaultConfig)
+		defaultManager.Start()
+	})
+	return defaultManager
+}
+
+// conn is a connection to the rx server, a *unixclient.UnixClient
+type conn interface {
+	Send(path string, dataToSend interface{}, timeoutInSeconds int, expectedResponse interface{}, expectedType string) (*unixclient.Response, error)
+	Close()
+}
+
+// lockedConn sends one request at a time, a unix client reads the reply to a request off the same socket so two
+// requests on one connection can not overlap
+type lockedConn struct {
+	mu sync.Mutex
+	conn
+}
+
+func (c *lockedConn) Send(path string, dataToSend interface{}, timeoutInSeconds int, expectedResponse interface{}, expectedType string) (*unixclient.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Send(path, dataToSend, timeoutInSeconds, expectedResponse, expectedType)
+}
+
+// Manager keeps a connection to the rx server, a failed request drops the connection and the next request or health
+// check connects again, backing off while the server is down so every message does not wait on a dial
+// the lock is only held to read or swap the connection, a slow request does not hold up a health check
+type Manager struct {
+	routes
+	config Config
+	dial   func(socket string) (conn, error)
+	probe  func(socket string, timeout time.Duration) error
+
+	mu        sync.Mutex
+	conn      conn
+	lastError error
+	retry     time.Duration
+	nextDial  time.Time
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+}
+
+func NewManager(config Config) *Manager {
+	return newManager(config, dialUnix, probeUnix)
+}
+
+func newManager(config Config, dial func(socket string) (conn, error), probe func(socket string, timeout time.Duration) error) *Manager {
+	defaults := DefaultConfig()
+	if config.Socket == "" {
+		config.Socket = defaults.Socket
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.RetryMin <= 0 {
+		config.RetryMin = defaults.RetryMin
+	}
+	if config.RetryMax < config.RetryMin {
+		config.RetryMax = config.RetryMin
+	}
+	m := &Manager{
+		config: config,
+		dial:   dial,
+		probe:  probe,
+		retry:  config.RetryMin,
+		stop:   make(chan struct{}),
+	}
+	m.routes = routes{send: m.send, timeout: seconds(config.Timeout)}
+	return m
+}
+
+func dialUnix(socket string) (conn, error) {
+	c, err := unixclient.NewUnixClient(socket)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func probeUnix(socket string, timeout time.Duration) error {
+	c, err := net.DialTimeout("unix", socket, timeout)
+	if err != nil {
+		return err
+	}
+	return c.Close()
+}
+
+// seconds rounds up to whole seconds, the rx server has no finer timeout
+func seconds(d time.Duration) int {
+	s := int((d + time.Second - 1) / time.Second)
+	if s < 1 {
+		return 1
+	}
+	return s
+}
+
+// Start runs the health checks, a HealthInterval of 0 disables them and the connection is only made on a request
+func (m *Manager) Start() {
+	if m.config.HealthInterval <= 0 {
+		return
+	}
+	m.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(m.config.HealthInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-m.stop:
+					return
+				case <-ticker.C:
+					m.Health()
+				}
+			}
+		}()
+	})
+}
+
+// Close stops the health checks and closes the connection
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stop)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.drop(ErrDisconnected)
+	})
+}
+
+// Health checks the rx server is still listening and connects if there is no connection
+func (m *Manager) Health() error {
+	m.mu.Lock()
+	c := m.conn
+	if c == nil {
+		_, err := m.connect()
+		m.mu.Unlock()
+		return err
+	}
+	m.mu.Unlock()
+	if err := m.probe(m.config.Socket, m.config.Timeout); err != nil {
+		m.dropConn(c, err)
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) Connected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.conn != nil
+}
+
+// LastError is the error that dropped the connection or stopped the last connect, nil when connected
+func (m *Manager) LastError() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastError
+}
+
+func (m *Manager) Send(path string, body, out any) error {
+	return m.send(path, body, out, m.routes.timeout)
+}
+
+func (m *Manager) send(path string, body, out any, timeout int) error {
+	m.mu.Lock()
+	c, err := m.connect()
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Send(path, body, timeout, out, "any")
+	if err != nil {
+		m.dropConn(c, err)
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if resp != nil && resp.Status == "Error" {
+		return fmt.Errorf("%s: %v", path, resp.Error)
+	}
+	return nil
+}
+
+// connect returns the open connection or dials a new one, must be called with the lock held
+func (m *Manager) connect() (conn, error) {
+	if m.conn != nil {
+		return m.conn, nil
+	}
+	if time.Now().Before(m.nextDial) {
+		return nil, fmt.Errorf("%w: %v", ErrDisconnected, m.lastError)
+	}
+	c, err := m.dial(m.config.Socket)
+	if err != nil {
+		m.lastError = err
+		m.nextDial = time.Now().Add(m.retry)
+		m.retry = min(m.retry*2, m.config.RetryMax)
+		return nil, fmt.Errorf("%w: %v", ErrDisconnected, err)
+	}
+	m.conn = &lockedConn{conn: c}
+	m.lastError = nil
+	m.retry = m.config.RetryMin
+	m.nextDial = time.Time{}
+	return m.conn, nil
+}
+
+// dropConn drops the connection after an error on it, unless it has already been replaced
+func (m *Manager) dropConn(c conn, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn == c {
+		m.drop(err)
+	}
+}
+
+// drop closes the connection after an error, must be called with the lock held
+func (m *Manager) drop(err error) {
+	if m.conn != nil {
+		m.conn.Close()
+		m.conn = nil
 	}
-	return rxClient, nil
+	m.lastError = err
 }
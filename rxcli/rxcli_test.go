@@ -0,0 +1,182 @@
+package rxcli
+
+import (
+	"errors"
+	"github.com/NubeIO/rxclient"
+	"github.com/NubeIO/unixclient"
+	"sync"
+	"testing"
+	"time"
+)
+
+var _ Client = (*Manager)(nil)
+var _ Client = (*Fake)(nil)
+
+type testConn struct {
+	fail   error
+	closed bool
+	block  chan struct{} // a request waits on it when set
+}
+
+func (c *testConn) Send(path string, _ interface{}, _ int, _ interface{}, _ string) (*unixclient.Response, error) {
+	if c.block != nil {
+		<-c.block
+	}
+	if c.fail != nil {
+		return nil, c.fail
+	}
+	return &unixclient.Response{Status: "Success"}, nil
+}
+
+func (c *testConn) Close() {
+	c.closed = true
+}
+
+type testServer struct {
+	mu    sync.Mutex
+	up    bool
+	dials int
+	conns []*testConn
+	block chan struct{}
+}
+
+func (s *testServer) dial(string) (conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dials++
+	if !s.up {
+		return nil, errors.New("connection refused")
+	}
+	c := &testConn{block: s.block}
+	s.conns = append(s.conns, c)
+	return c, nil
+}
+
+func (s *testServer) probe(string, time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.up {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (s *testServer) setUp(up bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.up = up
+}
+
+func TestManagerReconnect(t *testing.T) {
+	server := &testServer{}
+	m := newManager(Config{RetryMin: 20 * time.Millisecond, RetryMax: 40 * time.Millisecond}, server.dial, server.probe)
+
+	if err := m.Send("users/all", nil, nil); !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("expected ErrDisconnected, got %v", err)
+	}
+	// backing off, the server is not dialled again straight away
+	m.Send("users/all", nil, nil)
+	if server.dials != 1 {
+		t.Fatalf("expected 1 dial while backing off, got %d", server.dials)
+	}
+
+	server.setUp(true)
+	time.Sleep(25 * time.Millisecond)
+	if err := m.Send("users/all", nil, nil); err != nil {
+		t.Fatalf("expected to reconnect: %v", err)
+	}
+	if !m.Connected() || m.LastError() != nil {
+		t.Fatal("expected to be connected")
+	}
+
+	// a failed request drops the connection
+	server.conns[0].fail = errors.New("broken pipe")
+	if err := m.Send("users/all", nil, nil); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	if m.Connected() || !server.conns[0].closed {
+		t.Fatal("expected the connection to be closed")
+	}
+	if err := m.Send("users/all", nil, nil); err != nil {
+		t.Fatalf("expected a new connection: %v", err)
+	}
+
+	// the health check notices the server going away
+	server.setUp(false)
+	if err := m.Health(); err == nil || m.Connected() {
+		t.Fatal("expected the health check to drop the connection")
+	}
+	server.setUp(true)
+	if err := m.Health(); err != nil || !m.Connected() {
+		t.Fatalf("expected the health check to reconnect: %v", err)
+	}
+	m.Close()
+	if m.Connected() {
+		t.Fatal("expected close to drop the connection")
+	}
+}
+
+func TestManagerSlowRequest(t *testing.T) {
+	server := &testServer{up: true, block: make(chan struct{})}
+	m := newManager(Config{}, server.dial, server.probe)
+	sent := make(chan error)
+	go func() {
+		sent <- m.Send("cmd/run", nil, nil)
+	}()
+	// the health check does not wait for the request
+	checked := make(chan error)
+	go func() {
+		checked <- m.Health()
+	}()
+	select {
+	case err := <-checked:
+		if err != nil {
+			t.Errorf("expected the health check to pass: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the health check to return while a request is waiting")
+	}
+	close(server.block)
+	if err := <-sent; err != nil {
+		t.Errorf("expected the request to be sent: %v", err)
+	}
+}
+
+func TestManagerHealthLoop(t *testing.T) {
+	server := &testServer{up: true}
+	m := newManager(Config{HealthInterval: 5 * time.Millisecond}, server.dial, server.probe)
+	m.Start()
+	defer m.Close()
+	deadline := time.Now().Add(time.Second)
+	for !m.Connected() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the health check to connect")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFake(t *testing.T) {
+	f := NewFake()
+	f.Handle("users/all", func(any) (any, error) {
+		return []map[string]any{{"uuid": "u1", "isAdmin": true}}, nil
+	})
+	users, err := f.UserAll()
+	if err != nil || len(users) != 1 || users[0].UUID != "u1" || !users[0].IsAdmin {
+		t.Fatalf("unexpected users: %v %v", users, err)
+	}
+	if resp := f.IPValidation("1.2.3.4"); !resp.IsError {
+		t.Error("expected an error for a route with no handler")
+	}
+	f.SetDown(true)
+	if _, err := f.RunCommand(&rxclient.CommandBody{Command: "ls"}); !errors.Is(err, ErrDisconnected) {
+		t.Errorf("expected ErrDisconnected, got %v", err)
+	}
+	if f.Health() == nil {
+		t.Error("expected the health check to fail")
+	}
+	requests := f.Requests()
+	if len(requests) != 3 || requests[2].Path != "cmd/run" {
+		t.Errorf("unexpected requests: %+v", requests)
+	}
+}
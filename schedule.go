@@ -0,0 +1,160 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/schedule"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"time"
+)
+
+var Schedule scheduleObject
+
+const scheduleName = "schedule"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryTime, name: scheduleName, export: "Schedule", node: &Schedule})
+}
+
+var scheduleOutput = ports.Bool(constants.Output)
+var scheduleValue = ports.Float(constants.Value)
+var scheduleNext = ports.String(constants.Next)
+
+type scheduleSettings struct {
+	schedule.Config
+	OnValue  float64 `json:"onValue"`  // value output when the schedule is on
+	OffValue float64 `json:"offValue"` // value output when the schedule is off
+	Interval int     `json:"interval"` // ms between each evaluation
+}
+
+var scheduleSchema = func() *schemas.Schema {
+	s := schemas.New("Schedule")
+	s.String("timezone", "Timezone", "").Describe("IANA name eg; Australia/Sydney, empty is UTC")
+	s.Array("weekly", "Weekly", map[string]schema.Property{
+		"days":  {Type: schemas.TypeArray, Title: "Days", Items: &schema.Property{Type: schemas.TypeString, Enum: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}},
+		"start": {Type: schemas.TypeString, Title: "Start", Description: "HH:MM"},
+		"end":   {Type: schemas.TypeString, Title: "End", Description: "HH:MM"},
+	})
+	s.Array("cron", "Cron", map[string]schema.Property{
+		"on":  {Type: schemas.TypeString, Title: "On", Description: "cron expression for the schedule turning on"},
+		"off": {Type: schemas.TypeString, Title: "Off", Description: "cron expression for the schedule turning off"},
+	})
+	s.Array("holidays", "Holidays", nil).Describe("YYYY-MM-DD, the schedule is off for the whole day")
+	s.Number("onValue", "On value", 1).Describe("value output when the schedule is on")
+	s.Number("offValue", "Off value", 0).Describe("value output when the schedule is off")
+	s.Integer("interval", "Interval (ms)", 1000).AtLeast(100).Describe("time between each evaluation")
+	return s
+}()
+
+func defaultScheduleSettings() *scheduleSettings {
+	return &scheduleSettings{
+		OnValue:  1,
+		OffValue: 0,
+		Interval: 1000,
+	}
+}
+
+// scheduleObject outputs the state of a cron or weekly schedule and the time of the next change
+type scheduleObject struct {
+	rxlib.Object
+	settings  *scheduleSettings
+	schedule  *schedule.Schedule
+	clock     clock.Clock
+	lastState *bool
+	loaded    *loadedSettings
+	stop      chan struct{}
+}
+
+func NewScheduleObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(scheduleName, objectUUID, name, pluginName), bus)
+	ports.AddOutputs(object, scheduleOutput, scheduleValue, scheduleNext)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryTime,
+	})
+	s := defaultScheduleSettings()
+	loaded := loadSettings(object, scheduleSchema, settings, s)
+	sch, err := schedule.New(&s.Config)
+	if err != nil {
+		object.AddValidationResult("settings", err.Error())
+	}
+	return &scheduleObject{
+		Object:   object,
+		settings: s,
+		schedule: sch,
+		clock:    clock.System(),
+		loaded:   loaded,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (n *scheduleObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewScheduleObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+func (n *scheduleObject) CallSchema() *schema.Generated {
+	return scheduleSchema.Generated()
+}
+
+// UpdateSettings checks the new settings, the schedule is built on create so a change needs a restart
+func (n *scheduleObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *scheduleObject) Start() {
+	if n.Loaded() || n.schedule == nil {
+		return
+	}
+	n.SetLoaded(true)
+	go func() {
+		ticker := time.NewTicker(time.Duration(n.settings.Interval) * time.Millisecond)
+		defer ticker.Stop()
+		n.evaluate()
+		for {
+			select {
+			case <-ticker.C:
+				n.evaluate()
+			case <-n.stop:
+				return
+			}
+		}
+	}()
+}
+
+// evaluate publishes the outputs when the state has changed since the last evaluation
+func (n *scheduleObject) evaluate() {
+	now := n.clock.Now()
+	state := n.schedule.State(now)
+	if n.lastState != nil && *n.lastState == state {
+		return
+	}
+	n.lastState = &state
+
+	value := n.settings.OffValue
+	if state {
+		value = n.settings.OnValue
+	}
+	var nextTransition string
+	if next, _, ok := n.schedule.NextTransition(now); ok {
+		nextTransition = next.Format(time.RFC3339)
+	}
+	publishOutput(n, scheduleOutput, state)
+	publishOutput(n, scheduleValue, value)
+	publishOutput(n, scheduleNext, nextTransition)
+}
+
+// SetClock replaces the clock the object reads the time from, tests use a fake clock
+func (n *scheduleObject) SetClock(c clock.Clock) {
+	n.clock = c
+}
+
+func (n *scheduleObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/rxcli"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"time"
+)
+
+var ServiceStatus serviceStatusObject
+
+const serviceStatusName = "service-status"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryDiagnostics, name: serviceStatusName, export: "ServiceStatus", node: &ServiceStatus})
+}
+
+// newRxClient returns the client of the objects that use the rx server, tests swap it for a rxcli.Fake
+var newRxClient = func() rxcli.Client {
+	return rxcli.Default()
+}
+
+var serviceActive = ports.Bool(constants.Active)
+var serviceStatus = ports.String(constants.Status)
+
+type serviceStatusSettings struct {
+	Unit     string `json:"unit"`     // systemd unit, eg; nginx.service
+	Interval int    `json:"interval"` // ms between checks
+	Timeout  int    `json:"timeout"`  // seconds the rx server waits on systemctl
+}
+
+func defaultServiceStatusSettings() *serviceStatusSettings {
+	return &serviceStatusSettings{
+		Interval: 10000,
+		Timeout:  5,
+	}
+}
+
+var serviceStatusSchema = func() *schemas.Schema {
+	s := schemas.New("Service status")
+	s.String("unit", "Unit", "").Require().Describe("systemd unit, eg; nginx.service")
+	s.Integer("interval", "Interval (ms)", 10000).AtLeast(1000).Describe("time between checks")
+	s.Integer("timeout", "Timeout (s)", 5).Range(1, 60).Describe("time the rx server waits on systemctl")
+	return s
+}()
+
+// serviceStatusObject asks the rx server for the status of a systemd unit on an interval, a failed request is sent on
+// the error output and active goes false
+type serviceStatusObject struct {
+	rxlib.Object
+	client    rxcli.Client
+	settings  *serviceStatusSettings
+	lastError string
+	loaded    *loadedSettings
+	stop      chan struct{}
+}
+
+func NewServiceStatusObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(serviceStatusName, objectUUID, name, pluginName), bus)
+	ports.AddOutputs(object, serviceActive, serviceStatus, errorOutput)
+	object.SetDetails(&rxlib.Details{
+		Category:   categoryDiagnostics,
+		ObjectType: rxlib.Service,
+	})
+	s := defaultServiceStatusSettings()
+	loaded := loadSettings(object, serviceStatusSchema, settings, s)
+	return &serviceStatusObject{
+		Object:   object,
+		client:   newRxClient(),
+		settings: s,
+		loaded:   loaded,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (n *serviceStatusObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewServiceStatusObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+func (n *serviceStatusObject) CallSchema() *schema.Generated {
+	return n.loaded.generated()
+}
+
+// UpdateSettings checks the new settings, the unit and interval are read on start so a change needs a restart
+func (n *serviceStatusObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *serviceStatusObject) Start() {
+	if n.Loaded() || n.settings.Unit == "" {
+		return
+	}
+	n.SetLoaded(true)
+	go func() {
+		ticker := time.NewTicker(ms(n.settings.Interval))
+		defer ticker.Stop()
+		n.check()
+		for {
+			select {
+			case <-ticker.C:
+				n.check()
+			case <-n.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (n *serviceStatusObject) check() {
+	status, err := n.client.SystemdStatus(n.settings.Unit, n.settings.Timeout)
+	n.setError(err)
+	if err != nil {
+		objectLog(n).Warn("failed to read the service status", "unit", n.settings.Unit, "err", err)
+		publishOutput(n, serviceActive, false)
+		return
+	}
+	publishOutput(n, serviceActive, status.IsActive)
+	publishOutput(n, serviceStatus, status.Status)
+}
+
+// setError publishes the error when it changes, nil clears the error
+func (n *serviceStatusObject) setError(err error) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	if message == n.lastError {
+		return
+	}
+	n.lastError = message
+	publishOutput(n, errorOutput, message)
+}
+
+func (n *serviceStatusObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"github.com/NubeIO/reactive-nodes/rxcli"
+	"github.com/NubeIO/rxclient"
+	"testing"
+	"time"
+)
+
+// useFakeRxClient makes new objects use the fake until the test ends
+func useFakeRxClient(t *testing.T, fake *rxcli.Fake) {
+	defaultClient := newRxClient
+	newRxClient = func() rxcli.Client { return fake }
+	t.Cleanup(func() { newRxClient = defaultClient })
+}
+
+func TestServiceStatus(t *testing.T) {
+	fake := rxcli.NewFake()
+	fake.Handle("cmd/systemctl/status", func(body any) (any, error) {
+		if unit := body.(*rxclient.CommandBody).Command; unit != "nginx.service" {
+			return nil, errors.New("unknown unit " + unit)
+		}
+		return &rxclient.StatusResp{Status: "active", IsActive: true}, nil
+	})
+	useFakeRxClient(t, fake)
+
+	h := nodetest.New(t, &ServiceStatus, map[string]any{"unit": "nginx.service"}).Start()
+	h.Expect(constants.Active, true)
+	h.Expect(constants.Status, "active")
+	h.ExpectNone(constants.Error, 20*time.Millisecond)
+	if requests := fake.Requests(); len(requests) != 1 || requests[0].Path != "cmd/systemctl/status" {
+		t.Errorf("Expected: 1 status request, Got: %+v", requests)
+	}
+}
+
+func TestServiceStatusDown(t *testing.T) {
+	fake := rxcli.NewFake()
+	fake.SetDown(true)
+	useFakeRxClient(t, fake)
+
+	h := nodetest.New(t, &ServiceStatus, map[string]any{"unit": "nginx.service"}).Start()
+	if message, _ := h.Next(constants.Error).(string); message == "" {
+		t.Error("expected an error while the rx server is down")
+	}
+	h.Expect(constants.Active, false)
+	h.ExpectNone(constants.Status, 20*time.Millisecond)
+}
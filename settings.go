@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// noSettingsSchema is the schema of objects without settings
+var noSettingsSchema = schemas.New("Settings")
+
+// restartKey is the validation result listing the settings that only apply once the object is restarted
+const restartKey = "settings.restart"
+
+// loadedSettings are the settings an object was created with, they are kept so updates made while the object is
+// running can be checked against them
+type loadedSettings struct {
+	schema   *schemas.Schema
+	defaults []byte // json of the defaults, an update is decoded over them the same as on load
+	settings any
+}
+
+// loadSettings checks the object settings against the schema and decodes them into out, out should be populated with
+// its defaults before calling, each problem is added as a validation result and that setting keeps its default
+func loadSettings(object rxlib.Object, s *schemas.Schema, settings *rxlib.Settings, out any) *loadedSettings {
+	defaults, _ := json.Marshal(out)
+	addSettingsErrors(object, s.Decode(settings, out))
+	return &loadedSettings{schema: s, defaults: defaults, settings: out}
+}
+
+func addSettingsErrors(object rxlib.Object, errs []error) {
+	for _, err := range errs {
+		var fieldErr *schemas.FieldError
+		if errors.As(err, &fieldErr) {
+			object.AddValidationResult("settings."+fieldErr.Key, fieldErr.Message)
+			continue
+		}
+		object.AddValidationResult("settings", err.Error())
+	}
+}
+
+// generated is the schema for the UI, an object without settings has an empty schema
+func (l *loadedSettings) generated() *schema.Generated {
+	if l == nil {
+		return noSettingsSchema.Generated()
+	}
+	return l.schema.Generated()
+}
+
+// update checks new settings and decodes them into a new value of the loaded type, ok is false and nothing is stored
+// if a setting is invalid. The object applies the live settings from next, any other setting that differs from the
+// loaded settings is reported as needing a restart. object must be the base object so storing the settings does not
+// call back into the node
+func (l *loadedSettings) update(object rxlib.Object, settings *rxlib.Settings) (next any, ok bool) {
+	if l == nil {
+		object.UpdateSettings(settings)
+		return nil, false
+	}
+	object.DeleteValidation("settings")
+	for _, f := range l.schema.Fields() {
+		object.DeleteValidation("settings." + f.Key)
+	}
+	next = reflect.New(reflect.TypeOf(l.settings).Elem()).Interface()
+	if err := json.Unmarshal(l.defaults, next); err != nil {
+		object.AddValidationResult("settings", err.Error())
+		return nil, false
+	}
+	if errs := l.schema.Decode(settings, next); len(errs) > 0 {
+		addSettingsErrors(object, errs)
+		return nil, false
+	}
+	object.UpdateSettings(settings)
+	object.DeleteValidation(restartKey)
+	if restart := l.restart(next); len(restart) > 0 {
+		object.AddValidationResult(restartKey, fmt.Sprintf("restart the object to apply %s", strings.Join(restart, ", ")))
+	}
+	return next, true
+}
+
+// restart returns the settings in next that differ from the loaded settings and can not be changed while running
+func (l *loadedSettings) restart(next any) []string {
+	changed, err := l.schema.Changed(l.settings, next)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var restart []string
+	for _, key := range changed {
+		if !l.schema.Field(key).LiveUpdate {
+			restart = append(restart, key)
+		}
+	}
+	return restart
+}
+
+// objectLoop hands settings updates to the goroutine of a running object so the settings never change while a message
+// is being handled, an update is applied straight away when the goroutine never started or has returned
+type objectLoop struct {
+	updates chan func()
+
+	mu   sync.Mutex
+	done chan struct{} // closed when the goroutine returns, nil until it starts
+}
+
+func newObjectLoop() *objectLoop {
+	return &objectLoop{updates: make(chan func())}
+}
+
+// run is called by Start just before the goroutine starts, the goroutine defers the returned func
+func (l *objectLoop) run() (exit func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	done := make(chan struct{})
+	l.done = done
+	return func() { close(done) }
+}
+
+func (l *objectLoop) running() chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.done
+}
+
+// apply runs the update on the goroutine, or directly if nothing is receiving updates
+func (l *objectLoop) apply(update func()) {
+	if done := l.running(); done != nil {
+		select {
+		case l.updates <- update:
+			return
+		case <-done:
+		}
+	}
+	update()
+}
+
+// wait blocks until the goroutine has returned, it returns straight away if the goroutine never started
+func (l *objectLoop) wait() {
+	if done := l.running(); done != nil {
+		<-done
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/rxcli"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"testing"
+	"time"
+)
+
+// TestSchemasMatchSettings checks each schema only has settings its struct decodes
+func TestSchemasMatchSettings(t *testing.T) {
+	testCases := []struct {
+		schema   *schemas.Schema
+		settings any
+	}{
+		{mathSchema, defaultMathSettings()},
+		{mathRoundSchema, &mathRoundSettings{}},
+		{mathClampSchema, &mathClampSettings{}},
+		{mathScaleSchema, &mathScaleSettings{}},
+		{mathTrigSchema, &mathTrigSettings{}},
+		{mathLogSchema, &mathLogSettings{}},
+		{mathExpressionSchema, &mathExpressionSettings{}},
+		{logicSchema, defaultLogicSettings()},
+		{comparisonValueSchema, &comparisonSettings{}},
+		{comparisonRangeSchema, &comparisonSettings{}},
+		{delaySchema, defaultTimerSettings()},
+		{widthSchema, defaultTimerSettings()},
+		{minOnOffSchema, defaultTimerSettings()},
+		{pingSchema, defaultProbeSettings()},
+		{dnsResolveSchema, defaultProbeSettings()},
+		{serviceStatusSchema, defaultServiceStatusSettings()},
+		{countSchema, defaultCountSettings()},
+		{changeOfValueSchema, &changeOfValueSettings{}},
+		{pidSchema, defaultPIDSettings()},
+		{scheduleSchema, defaultScheduleSettings()},
+		{streamStatsSchema, defaultStreamStatsSettings()},
+		{triggerSchema, defaultTriggerSettings()},
+		{modbusNetworkSchema, defaultModbusNetworkSettings()},
+		{modbusDeviceSchema, &modbusDeviceSettings{}},
+		{modbusPointSchema, &pointSettings{}},
+	}
+	for _, testCase := range testCases {
+		for _, err := range testCase.schema.CheckKeys(testCase.settings) {
+			t.Error(err)
+		}
+	}
+}
+
+// TestNodeSchemas checks every registered node publishes a schema
+func TestNodeSchemas(t *testing.T) {
+	useFakeRxClient(t, rxcli.NewFake())
+	bus := rxlib.NewEventBus()
+	for _, r := range nodeRegistry {
+		object := r.node.New("uuid-"+r.name, r.name, bus, nil)
+		s, ok := object.(interface{ CallSchema() *schema.Generated })
+		if !ok {
+			t.Errorf("%s has no schema", r.name)
+			continue
+		}
+		if s.CallSchema() == nil {
+			t.Errorf("%s returned a nil schema", r.name)
+		}
+	}
+}
+
+func TestTriggerSettingsValidation(t *testing.T) {
+	testCases := []struct {
+		settings map[string]any
+		expected []string
+	}{
+		{map[string]any{"mode": "sine", "period": 1000}, nil},
+		{map[string]any{"mode": "noise"}, []string{"mode"}},
+		{map[string]any{"interval": 0, "mode": "replay"}, []string{"interval", "file"}},
+		// the file is only checked in replay mode
+		{map[string]any{"mode": "random", "file": 1}, nil},
+	}
+	for _, testCase := range testCases {
+		values, err := schemas.Values(&rxlib.Settings{Value: testCase.settings})
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs := triggerSchema.Validate(values)
+		var keys []string
+		for _, err := range errs {
+			keys = append(keys, err.Key)
+		}
+		if len(keys) != len(testCase.expected) {
+			t.Errorf("Settings: %v, Expected: %v, Got: %v", testCase.settings, testCase.expected, keys)
+			continue
+		}
+		for i := range keys {
+			if keys[i] != testCase.expected[i] {
+				t.Errorf("Settings: %v, Expected: %v, Got: %v", testCase.settings, testCase.expected, keys)
+			}
+		}
+	}
+}
+
+func TestTriggerUpdateSettings(t *testing.T) {
+	bus := rxlib.NewEventBus()
+	n := NewTriggerObject("trigger-1", "trigger", bus, &rxlib.Settings{Value: map[string]any{"mode": "sine", "interval": 1000}}).(*triggerFloat)
+
+	update := &rxlib.Settings{Value: map[string]any{"mode": "random", "interval": 500, "amplitude": 3}}
+	n.UpdateSettings(update)
+	if n.settings.Interval != 500 || n.settings.Amplitude != 3 {
+		t.Errorf("expected the live settings to be applied, got: %+v", n.settings)
+	}
+	if n.settings.Mode != triggerModeSine {
+		t.Errorf("Expected: the mode to need a restart, Got: %s", n.settings.Mode)
+	}
+	if n.GetSettings() != update {
+		t.Error("expected the settings to be stored")
+	}
+
+	next, _ := n.loaded.update(n.Object, update)
+	if restart := n.loaded.restart(next); len(restart) != 1 || restart[0] != "mode" {
+		t.Errorf("Expected: [mode], Got: %v", restart)
+	}
+
+	// an invalid update is not applied
+	n.UpdateSettings(&rxlib.Settings{Value: map[string]any{"mode": "sine", "interval": "fast"}})
+	if n.settings.Interval != 500 || n.GetSettings() != update {
+		t.Errorf("expected the invalid update to be ignored, got interval: %d", n.settings.Interval)
+	}
+}
+
+func TestCountUpdateSettings(t *testing.T) {
+	bus := rxlib.NewEventBus()
+	n := NewCountObject("count-1", "count", bus, &rxlib.Settings{Value: map[string]any{"startCount": 8, "persist": false}}).(*countObject)
+	n.UpdateSettings(&rxlib.Settings{Value: map[string]any{"startCount": 8, "max": 5, "persist": false}})
+	if n.counter.Count() != 5 {
+		t.Errorf("Expected: 5, Got: %d", n.counter.Count())
+	}
+	if n.settings.Max == nil || *n.settings.Max != 5 {
+		t.Error("expected the new limit to be kept")
+	}
+}
+
+// TestUpdateSettingsNotRunning checks an update does not wait for a goroutine that never started or has returned
+func TestUpdateSettingsNotRunning(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   rxlib.Object
+		settings map[string]any
+	}{
+		{"deleted count", NewCountObject("count-2", "count", rxlib.NewEventBus(), &rxlib.Settings{Value: map[string]any{"persist": false}}), map[string]any{"step": 2, "persist": false}},
+		{"deleted trigger", NewTriggerObject("trigger-2", "trigger", rxlib.NewEventBus(), nil), map[string]any{"interval": 500}},
+		{"deleted modbus network", NewModbusNetwork("network-2", "network", rxlib.NewEventBus(), nil), map[string]any{"pollInterval": 500}},
+	}
+	for _, test := range tests {
+		test.object.Start()
+		test.object.Delete()
+		updated := make(chan struct{})
+		go func() {
+			test.object.UpdateSettings(&rxlib.Settings{Value: test.settings})
+			close(updated)
+		}()
+		select {
+		case <-updated:
+		case <-time.After(time.Second):
+			t.Errorf("%s: UpdateSettings did not return", test.name)
+		}
+	}
+
+	// started without its input the count has no goroutine
+	n := NewCountObject("count-3", "count", rxlib.NewEventBus(), &rxlib.Settings{Value: map[string]any{"persist": false}}).(*countObject)
+	n.SetLoaded(true)
+	n.UpdateSettings(&rxlib.Settings{Value: map[string]any{"step": 3, "persist": false}})
+	if n.settings.Step != 3 {
+		t.Errorf("Expected: 3, Got: %d", n.settings.Step)
+	}
+}
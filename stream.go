@@ -0,0 +1,162 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/stream"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"time"
+)
+
+var StreamStats streamStatsObject
+
+const streamStatsName = "stream-stats"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryStream, name: streamStatsName, export: "StreamStats", node: &StreamStats})
+}
+
+var streamStatsInput = ports.Any(constants.Input)
+var streamStatsCount = ports.Float(constants.Count)
+var streamStatsSum = ports.Float(constants.Sum)
+var streamStatsMin = ports.Float(constants.Min)
+var streamStatsMax = ports.Float(constants.Max)
+var streamStatsMean = ports.Float(constants.Mean)
+var streamStatsStdDev = ports.Float(constants.StdDev)
+var streamStatsRate = ports.Float(constants.Rate) // messages a second
+var streamStatsAvgMessageCount = ports.New(constants.AvgMessageCount, stream.PortTypeAvgMessageCount)
+
+type streamStatsSettings struct {
+	WindowCount int `json:"windowCount"` // max messages in the window, 0 for no limit
+	WindowTime  int `json:"windowTime"`  // ms, max age of a message in the window, 0 for no limit
+	Interval    int `json:"interval"`    // ms, re-publish the stats even when no messages arrive, 0 to only publish on a message
+}
+
+var streamStatsSchema = func() *schemas.Schema {
+	s := schemas.New("Stream stats")
+	s.Integer("windowCount", "Window count", 0).AtLeast(0).Describe("max messages in the window, 0 for no limit")
+	s.Integer("windowTime", "Window time (ms)", 60000).AtLeast(0).Describe("max age of a message in the window, 0 for no limit")
+	s.Integer("interval", "Interval (ms)", 0).AtLeast(0).Describe("re-publish the stats even when no messages arrive, 0 to only publish on a message")
+	return s
+}()
+
+func defaultStreamStatsSettings() *streamStatsSettings {
+	return &streamStatsSettings{
+		WindowTime: 60000,
+	}
+}
+
+// streamStatsObject publishes windowed statistics and message rates of the messages on its input
+type streamStatsObject struct {
+	rxlib.Object
+	settings *streamStatsSettings
+	window   *stream.Window
+	clock    clock.Clock
+	loaded   *loadedSettings
+	stop     chan struct{}
+}
+
+func NewStreamStatsObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(streamStatsName, objectUUID, name, pluginName), bus)
+	ports.AddInputs(object, streamStatsInput)
+	ports.AddOutputs(object, streamStatsCount, streamStatsSum, streamStatsMin, streamStatsMax, streamStatsMean, streamStatsStdDev, streamStatsRate, streamStatsAvgMessageCount)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryStream,
+	})
+	s := defaultStreamStatsSettings()
+	loaded := loadSettings(object, streamStatsSchema, settings, s)
+	if s.WindowCount <= 0 && s.WindowTime <= 0 {
+		object.AddValidationResult("settings", "a window count or window time is needed, using the default window time")
+		s.WindowTime = defaultStreamStatsSettings().WindowTime
+	}
+	return &streamStatsObject{
+		Object:   object,
+		settings: s,
+		window:   stream.NewWindow(s.WindowCount, time.Duration(s.WindowTime)*time.Millisecond),
+		clock:    clock.System(),
+		loaded:   loaded,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (n *streamStatsObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewStreamStatsObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+func (n *streamStatsObject) CallSchema() *schema.Generated {
+	return streamStatsSchema.Generated()
+}
+
+// UpdateSettings checks the new settings, the window is built on create so a change needs a restart
+func (n *streamStatsObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *streamStatsObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputChannel, exists := n.BusChannel(constants.Input)
+	if !exists {
+		objectLog(n).Error("input channel does not exist", "port", constants.Input)
+		return
+	}
+	go func() {
+		var tick <-chan time.Time
+		if n.settings.Interval > 0 {
+			ticker := time.NewTicker(time.Duration(n.settings.Interval) * time.Millisecond)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-n.stop:
+				return
+			case msg, ok := <-inputChannel:
+				if !ok {
+					return
+				}
+				done := received(n, constants.Input)
+				var value any
+				if msg.Port != nil {
+					value = msg.Port.Value
+				}
+				n.window.Add(n.clock.Now(), value)
+				n.publish()
+				done()
+			case <-tick:
+				n.publish()
+			}
+		}
+	}()
+}
+
+func (n *streamStatsObject) publish() {
+	stats := n.window.Stats(n.clock.Now())
+	publishOutput(n, streamStatsCount, stats.Count)
+	publishOutput(n, streamStatsSum, stats.Sum)
+	publishOutput(n, streamStatsMin, stats.Min)
+	publishOutput(n, streamStatsMax, stats.Max)
+	publishOutput(n, streamStatsMean, stats.Mean)
+	publishOutput(n, streamStatsStdDev, stats.StdDev)
+	publishOutput(n, streamStatsRate, stats.Rate.PerSec)
+	publishOutput(n, streamStatsAvgMessageCount, stats.Rate)
+}
+
+// SetClock replaces the clock the object reads the time from, tests use a fake clock
+func (n *streamStatsObject) SetClock(c clock.Clock) {
+	n.clock = c
+}
+
+func (n *streamStatsObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
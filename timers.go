@@ -0,0 +1,270 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive"
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/clock"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
+	"github.com/NubeIO/reactive-nodes/nodes/timer"
+	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"time"
+)
+
+var OnDelay onDelayObject
+var OffDelay offDelayObject
+var Pulse pulseObject
+var Monostable monostableObject
+var MinOnOff minOnOffObject
+
+const onDelayName = "delay-on"
+const offDelayName = "delay-off"
+const pulseName = "pulse"
+const monostableName = "monostable"
+const minOnOffName = "min-on-off"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryTime, name: onDelayName, export: "OnDelay", node: &OnDelay})
+	registerNode(nodeRegistration{category: categoryTime, name: offDelayName, export: "OffDelay", node: &OffDelay})
+	registerNode(nodeRegistration{category: categoryTime, name: pulseName, export: "Pulse", node: &Pulse})
+	registerNode(nodeRegistration{category: categoryTime, name: monostableName, export: "Monostable", node: &Monostable})
+	registerNode(nodeRegistration{category: categoryTime, name: minOnOffName, export: "MinOnOff", node: &MinOnOff})
+}
+
+var timerInput = ports.Bool(constants.Input)
+var timerReset = ports.Bool(constants.Reset)
+var timerOutput = ports.Bool(constants.Output)
+
+type timerSettings struct {
+	Delay      int `json:"delay"`      // ms, on-delay and off-delay
+	Width      int `json:"width"`      // ms, pulse and monostable
+	MinOn      int `json:"minOn"`      // ms, min-on-off
+	MinOff     int `json:"minOff"`     // ms, min-on-off
+	Resolution int `json:"resolution"` // ms between re-evaluating the timer while waiting
+}
+
+func defaultTimerSettings() *timerSettings {
+	return &timerSettings{
+		Delay:      5000,
+		Width:      5000,
+		MinOn:      60000,
+		MinOff:     60000,
+		Resolution: 100,
+	}
+}
+
+// timerSchema builds the schema of a timer from the settings it uses, the resolution is shared by them all
+func timerSchema(title string, add func(s *schemas.Schema)) *schemas.Schema {
+	s := schemas.New(title)
+	add(s)
+	s.Integer("resolution", "Resolution (ms)", 100).Range(10, 60000).Describe("time between re-evaluating the timer while waiting")
+	return s
+}
+
+var delaySchema = timerSchema("Delay", func(s *schemas.Schema) {
+	s.Integer("delay", "Delay (ms)", 5000).AtLeast(0)
+})
+
+var widthSchema = timerSchema("Pulse", func(s *schemas.Schema) {
+	s.Integer("width", "Width (ms)", 5000).AtLeast(0)
+})
+
+var minOnOffSchema = timerSchema("Min on off", func(s *schemas.Schema) {
+	s.Integer("minOn", "Min on (ms)", 60000).AtLeast(0)
+	s.Integer("minOff", "Min off (ms)", 60000).AtLeast(0)
+})
+
+func ms(v int) time.Duration {
+	return time.Duration(v) * time.Millisecond
+}
+
+// timerObject feeds its input into a timer state machine and publishes the output when it changes
+type timerObject struct {
+	rxlib.Object
+	timer      timer.Timer
+	clock      clock.Clock
+	resolution time.Duration
+	input      bool
+	output     *bool
+	loaded     *loadedSettings
+	stop       chan struct{}
+}
+
+func newTimerObject(objectID, objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings, settingsSchema *schemas.Schema, newTimer func(s *timerSettings) timer.Timer) timerObject {
+	object := reactive.NewBaseObject(reactive.ObjectInfo(objectID, objectUUID, name, pluginName), bus)
+	ports.AddInputs(object, timerInput, timerReset)
+	ports.AddOutputs(object, timerOutput)
+	object.SetDetails(&rxlib.Details{
+		Category: categoryTime,
+	})
+	s := defaultTimerSettings()
+	loaded := loadSettings(object, settingsSchema, settings, s)
+	return timerObject{
+		Object:     object,
+		timer:      newTimer(s),
+		clock:      clock.System(),
+		resolution: ms(s.Resolution),
+		loaded:     loaded,
+		stop:       make(chan struct{}),
+	}
+}
+
+func (n *timerObject) CallSchema() *schema.Generated {
+	return n.loaded.generated()
+}
+
+// UpdateSettings checks the new settings, the timer is built on create so a change needs a restart
+func (n *timerObject) UpdateSettings(settings *rxlib.Settings) {
+	n.loaded.update(n.Object, settings)
+}
+
+func (n *timerObject) Start() {
+	if n.Loaded() {
+		return
+	}
+	n.SetLoaded(true)
+	inputChannel, _ := n.BusChannel(constants.Input)
+	resetChannel, _ := n.BusChannel(constants.Reset)
+	go func() {
+		ticker := time.NewTicker(n.resolution)
+		defer ticker.Stop()
+		for {
+			done := func() {}
+			select {
+			case <-n.stop:
+				return
+			case msg, ok := <-inputChannel:
+				if !ok {
+					inputChannel = nil
+					continue
+				}
+				done = received(n, constants.Input)
+				n.input = inputBool(n, constants.Input, messageValue(msg))
+			case msg, ok := <-resetChannel:
+				if !ok {
+					resetChannel = nil
+					continue
+				}
+				done = received(n, constants.Reset)
+				if inputBool(n, constants.Reset, messageValue(msg)) {
+					n.timer.Reset()
+				}
+			case <-ticker.C:
+			}
+			n.update()
+			done()
+		}
+	}()
+}
+
+func (n *timerObject) update() {
+	out := n.timer.Update(n.clock.Now(), n.input)
+	if n.output != nil && *n.output == out {
+		return
+	}
+	n.output = &out
+	publishOutput(n, timerOutput, out)
+}
+
+// SetClock replaces the clock the object reads the time from, tests use a fake clock
+func (n *timerObject) SetClock(c clock.Clock) {
+	n.clock = c
+}
+
+func (n *timerObject) Delete() {
+	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
+	n.RemoveObjectFromRuntime()
+}
+
+// onDelayObject turns on once the input has been on for the delay
+type onDelayObject struct {
+	timerObject
+}
+
+func NewOnDelayObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &onDelayObject{
+		timerObject: newTimerObject(onDelayName, objectUUID, name, bus, settings, delaySchema, func(s *timerSettings) timer.Timer {
+			return timer.NewOnDelay(ms(s.Delay))
+		}),
+	}
+}
+
+func (n *onDelayObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewOnDelayObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+// offDelayObject stays on for the delay after the input turns off
+type offDelayObject struct {
+	timerObject
+}
+
+func NewOffDelayObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &offDelayObject{
+		timerObject: newTimerObject(offDelayName, objectUUID, name, bus, settings, delaySchema, func(s *timerSettings) timer.Timer {
+			return timer.NewOffDelay(ms(s.Delay))
+		}),
+	}
+}
+
+func (n *offDelayObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewOffDelayObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+// pulseObject outputs a fixed width pulse on a rising edge
+type pulseObject struct {
+	timerObject
+}
+
+func NewPulseObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &pulseObject{
+		timerObject: newTimerObject(pulseName, objectUUID, name, bus, settings, widthSchema, func(s *timerSettings) timer.Timer {
+			return timer.NewPulse(ms(s.Width))
+		}),
+	}
+}
+
+func (n *pulseObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewPulseObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+// monostableObject is a pulse that restarts on every rising edge
+type monostableObject struct {
+	timerObject
+}
+
+func NewMonostableObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &monostableObject{
+		timerObject: newTimerObject(monostableName, objectUUID, name, bus, settings, widthSchema, func(s *timerSettings) timer.Timer {
+			return timer.NewMonostable(ms(s.Width))
+		}),
+	}
+}
+
+func (n *monostableObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewMonostableObject(objectUUID, name, bus, settings)
+	return newObject
+}
+
+// minOnOffObject enforces a minimum on and off run time, eg; for a compressor
+type minOnOffObject struct {
+	timerObject
+}
+
+func NewMinOnOffObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	return &minOnOffObject{
+		timerObject: newTimerObject(minOnOffName, objectUUID, name, bus, settings, minOnOffSchema, func(s *timerSettings) timer.Timer {
+			return timer.NewMinOnOff(ms(s.MinOn), ms(s.MinOff))
+		}),
+	}
+}
+
+func (n *minOnOffObject) New(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
+	newObject := NewMinOnOffObject(objectUUID, name, bus, settings)
+	return newObject
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"testing"
+	"time"
+)
+
+func TestOnDelay(t *testing.T) {
+	h := nodetest.New(t, &OnDelay, map[string]any{"delay": 5000, "resolution": 10}).Start()
+	h.Expect(constants.Output, false)
+	h.Send(constants.Input, true)
+	// the input is read on the object's goroutine, let it start the delay before moving the clock
+	h.ExpectNone(constants.Output, 20*time.Millisecond)
+	h.Advance(4 * time.Second)
+	h.ExpectNone(constants.Output, 50*time.Millisecond)
+	h.Advance(time.Second)
+	h.Expect(constants.Output, true)
+	h.Send(constants.Input, false)
+	h.Expect(constants.Output, false)
+}
+
+func TestPulseReset(t *testing.T) {
+	h := nodetest.New(t, &Pulse, map[string]any{"width": 1000, "resolution": 10}).Start()
+	h.Expect(constants.Output, false)
+	h.Send(constants.Input, true)
+	h.Expect(constants.Output, true)
+	h.Send(constants.Reset, true)
+	h.Expect(constants.Output, false)
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
-	"fmt"
 	"github.com/NubeIO/reactive"
 	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/generator"
+	"github.com/NubeIO/reactive-nodes/helpers/ports"
+	"github.com/NubeIO/reactive-nodes/helpers/schemas"
 	"github.com/NubeIO/rxlib"
+	"github.com/NubeIO/schema"
+	"math"
 	"math/rand"
 	"time"
 )
@@ -12,22 +16,142 @@ import (
 // exports
 var Trigger triggerFloat
 
-// triggerFloat generates random values at regular intervals.
+const triggerName = "trigger"
+
+func init() {
+	registerNode(nodeRegistration{category: categoryTime, name: triggerName, export: "Trigger", node: &Trigger})
+}
+
+var triggerEnable = ports.Bool(constants.Enable)
+var triggerFire = ports.Any(constants.Fire)
+var triggerOutput = ports.Float(constants.Output)
+
+type triggerMode string
+
+const (
+	triggerModeRandom    triggerMode = "random"
+	triggerModeGaussian  triggerMode = "gaussian"
+	triggerModeWalk      triggerMode = "random-walk"
+	triggerModeReplay    triggerMode = "replay"
+	triggerModeConstant  triggerMode = "constant"
+	triggerModeCounter   triggerMode = "counter"
+	triggerModeSine      triggerMode = "sine"
+	triggerModeSawtooth  triggerMode = "sawtooth"
+	triggerModeSquare    triggerMode = "square"
+	triggerModeTimestamp triggerMode = "timestamp"
+)
+
+type triggerSettings struct {
+	Interval  int         `json:"interval"`  // ms between each value
+	Jitter    int         `json:"jitter"`    // ms, a random delay between 0 and jitter is added to each interval
+	Mode      triggerMode `json:"mode"`      // see triggerMode
+	Seed      int64       `json:"seed"`      // random/gaussian/random-walk: 0 gives a different series on each start
+	Min       float64     `json:"min"`       // random/random-walk: lowest value
+	Max       float64     `json:"max"`       // random/random-walk: highest value
+	Mean      float64     `json:"mean"`      // gaussian
+	StdDev    float64     `json:"stdDev"`    // gaussian
+	Value     float64     `json:"value"`     // constant: the value, counter/random-walk: the start value
+	Step      float64     `json:"step"`      // counter: added on each fire, random-walk: max change on each fire
+	File      string      `json:"file"`      // replay: path to a csv file
+	Column    int         `json:"column"`    // replay: csv column, starting at 0
+	Loop      bool        `json:"loop"`      // replay: start again at the end of the file
+	Period    int         `json:"period"`    // sine/sawtooth/square: ms for one cycle
+	Amplitude float64     `json:"amplitude"` // sine/sawtooth/square: peak value from the offset
+	Offset    float64     `json:"offset"`    // sine/sawtooth/square: value the wave is centred on
+}
+
+var triggerSchema = func() *schemas.Schema {
+	random, gaussian, walk := string(triggerModeRandom), string(triggerModeGaussian), string(triggerModeWalk)
+	constant, counter, replay := string(triggerModeConstant), string(triggerModeCounter), string(triggerModeReplay)
+	sine, sawtooth, square := string(triggerModeSine), string(triggerModeSawtooth), string(triggerModeSquare)
+	s := schemas.New("Trigger")
+	s.Integer("interval", "Interval (ms)", 2000).AtLeast(10).Live().Describe("time between each value")
+	s.Integer("jitter", "Jitter (ms)", 0).AtLeast(0).Live().Describe("a random delay between 0 and jitter is added to each interval")
+	s.Enum("mode", "Mode", random, random, gaussian, walk, replay, constant, counter, sine, sawtooth, square, string(triggerModeTimestamp))
+	s.Integer("seed", "Seed", 0).Describe("0 gives a different series on each start").When("mode", random, gaussian, walk)
+	s.Number("min", "Min", 1).Describe("lowest value").When("mode", random, walk)
+	s.Number("max", "Max", 10).Describe("highest value").When("mode", random, walk)
+	s.Number("mean", "Mean", 0).When("mode", gaussian)
+	s.Number("stdDev", "Standard deviation", 1).AtLeast(0).When("mode", gaussian)
+	s.Number("value", "Value", 0).Live().Describe("constant: the value, counter and random-walk: the start value").When("mode", constant, counter, walk)
+	s.Number("step", "Step", 1).Live().Describe("counter: added on each fire, random-walk: max change on each fire").When("mode", counter, walk)
+	s.String("file", "File", "").Require().Describe("path to a csv file").When("mode", replay)
+	s.Integer("column", "Column", 0).AtLeast(0).Describe("csv column, starting at 0").When("mode", replay)
+	s.Bool("loop", "Loop", true).Describe("start again at the end of the file").When("mode", replay)
+	s.Integer("period", "Period (ms)", 60000).AtLeast(1).Live().Describe("time for one cycle").When("mode", sine, sawtooth, square)
+	s.Number("amplitude", "Amplitude", 1).Live().Describe("peak value from the offset").When("mode", sine, sawtooth, square)
+	s.Number("offset", "Offset", 0).Live().Describe("value the wave is centred on").When("mode", sine, sawtooth, square)
+	return s
+}()
+
+func defaultTriggerSettings() *triggerSettings {
+	return &triggerSettings{
+		Interval:  2000,
+		Mode:      triggerModeRandom,
+		Min:       1,
+		Max:       10,
+		StdDev:    1,
+		Step:      1,
+		Period:    60000,
+		Amplitude: 1,
+		Loop:      true,
+	}
+}
+
+// triggerFloat generates values at regular intervals.
 type triggerFloat struct {
 	rxlib.Object
-	stop chan struct{}
+	settings  *triggerSettings
+	generator generator.Generator
+	failed    bool // the generator could not be built, the object does not start
+	enabled   bool
+	count     float64
+	started   time.Time
+	loaded    *loadedSettings
+	loop      *objectLoop
+	stop      chan struct{}
 }
 
 // NewTriggerObject creates a new triggerFloat with the given ID, name, EventBus, and Flow.
 func NewTriggerObject(objectUUID, name string, bus *rxlib.EventBus, settings *rxlib.Settings) rxlib.Object {
-	object := reactive.NewBaseObject(reactive.ObjectInfo(trigger, objectUUID, name, pluginName), bus)
-	object.NewOutputPort(constants.Output, constants.Output, "float")
+	object := reactive.NewBaseObject(reactive.ObjectInfo(triggerName, objectUUID, name, pluginName), bus)
+	ports.AddInputs(object, triggerEnable, triggerFire)
+	ports.AddOutputs(object, triggerOutput)
 	object.AddDependencies(&rxlib.Dependencies{
 		RequiresRouter: true,
 	})
+	s := defaultTriggerSettings()
+	loaded := loadSettings(object, triggerSchema, settings, s)
+	gen, err := newTriggerGenerator(s)
+	if err != nil {
+		object.AddValidationResult("settings", err.Error())
+	}
 	return &triggerFloat{
-		Object: object,
-		stop:   make(chan struct{}),
+		Object:    object,
+		settings:  s,
+		generator: gen,
+		failed:    err != nil,
+		enabled:   true,
+		count:     s.Value,
+		loaded:    loaded,
+		loop:      newObjectLoop(),
+		stop:      make(chan struct{}),
+	}
+}
+
+// newTriggerGenerator returns the generator for the random modes, nil is returned for the other modes
+func newTriggerGenerator(s *triggerSettings) (generator.Generator, error) {
+	switch s.Mode {
+	case triggerModeGaussian:
+		return generator.NewGaussian(s.Seed, s.Mean, s.StdDev), nil
+	case triggerModeWalk:
+		return generator.NewRandomWalk(s.Seed, s.Value, s.Step, s.Min, s.Max), nil
+	case triggerModeReplay:
+		return generator.NewReplayFile(s.File, s.Column, s.Loop)
+	case triggerModeConstant, triggerModeCounter, triggerModeSine, triggerModeSawtooth, triggerModeSquare, triggerModeTimestamp:
+		return nil, nil
+	default:
+		return generator.NewUniform(s.Seed, s.Min, s.Max), nil
 	}
 }
 
@@ -36,40 +160,143 @@ func (n *triggerFloat) New(objectUUID, name string, bus *rxlib.EventBus, setting
 	return newObject
 }
 
+func (n *triggerFloat) CallSchema() *schema.Generated {
+	return triggerSchema.Generated()
+}
+
+// UpdateSettings applies the timing, counter and wave settings while the object runs, a new value restarts the counter
+// the mode and the settings of the random and replay generators build the generator on create so a change to them needs
+// a restart, the random walk keeps the value and step it was created with
+func (n *triggerFloat) UpdateSettings(settings *rxlib.Settings) {
+	next, ok := n.loaded.update(n.Object, settings)
+	if !ok {
+		return
+	}
+	s := next.(*triggerSettings)
+	n.loop.apply(func() {
+		if s.Value != n.settings.Value {
+			n.count = s.Value
+		}
+		n.settings.Interval = s.Interval
+		n.settings.Jitter = s.Jitter
+		n.settings.Value = s.Value
+		n.settings.Step = s.Step
+		n.settings.Period = s.Period
+		n.settings.Amplitude = s.Amplitude
+		n.settings.Offset = s.Offset
+	})
+}
+
+// Start does nothing if the generator could not be built, eg; the replay file is missing, so no made up values are sent
 func (n *triggerFloat) Start() {
+	if n.Loaded() || n.failed {
+		return
+	}
+	n.SetLoaded(true)
+	n.started = time.Now()
+	enableChannel, _ := n.BusChannel(constants.Enable)
+	fireChannel, _ := n.BusChannel(constants.Fire)
+	exit := n.loop.run()
 	go func() {
-		ticker := time.NewTicker(2000 * time.Millisecond)
-		defer ticker.Stop()
+		defer exit()
+		timer := time.NewTimer(n.nextInterval())
+		defer timer.Stop()
 		for {
 			select {
-			case <-ticker.C:
-				select {
-				case <-n.stop:
-					return // Stop triggering when the stopTrigger channel is closed
-				default:
-					ranValue := randFloat()
-					out := &rxlib.Port{
-						ID:        constants.Output,
-						Name:      constants.Output,
-						Value:     ranValue,
-						Direction: "output",
-						DataType:  "float",
+			case <-n.stop:
+				return // Stop triggering when the stop channel is closed
+			case apply := <-n.loop.updates:
+				apply()
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
 					}
-					fmt.Println("NEW VALUE:", "triggerFloat", out.Value)
-					n.PublishMessage(out, true)
 				}
+				timer.Reset(n.nextInterval())
+			case msg, ok := <-enableChannel:
+				if !ok {
+					enableChannel = nil
+					continue
+				}
+				done := received(n, constants.Enable)
+				n.enabled = inputBool(n, constants.Enable, messageValue(msg))
+				done()
+			case _, ok := <-fireChannel:
+				if !ok {
+					fireChannel = nil
+					continue
+				}
+				done := received(n, constants.Fire)
+				n.fire(time.Now())
+				done()
+			case now := <-timer.C:
+				if n.enabled {
+					n.fire(now)
+				}
+				timer.Reset(n.nextInterval())
 			}
 		}
 	}()
 }
 
+func (n *triggerFloat) fire(now time.Time) {
+	publishOutput(n, triggerOutput, n.nextValue(now))
+}
+
+func (n *triggerFloat) nextInterval() time.Duration {
+	interval := time.Duration(n.settings.Interval) * time.Millisecond
+	if n.settings.Jitter > 0 {
+		interval += time.Duration(rand.Int63n(int64(n.settings.Jitter)+1)) * time.Millisecond
+	}
+	return interval
+}
+
+// nextValue returns the value for the current mode, wave modes are based on the time since the object was started
+func (n *triggerFloat) nextValue(now time.Time) float64 {
+	s := n.settings
+	switch s.Mode {
+	case triggerModeConstant:
+		return s.Value
+	case triggerModeCounter:
+		value := n.count
+		n.count += s.Step
+		return value
+	case triggerModeSine, triggerModeSawtooth, triggerModeSquare:
+		return waveValue(s, now.Sub(n.started))
+	case triggerModeTimestamp:
+		return float64(now.UnixMilli())
+	default:
+		if n.generator == nil {
+			return 0
+		}
+		return n.generator.Next()
+	}
+}
+
+// waveValue returns the value of a wave for the elapsed time
+func waveValue(s *triggerSettings, elapsed time.Duration) float64 {
+	if s.Period <= 0 {
+		return s.Offset
+	}
+	period := time.Duration(s.Period) * time.Millisecond
+	phase := float64(elapsed%period) / float64(period) // 0 to 1
+	switch s.Mode {
+	case triggerModeSawtooth:
+		return s.Offset + s.Amplitude*(2*phase-1)
+	case triggerModeSquare:
+		if phase < 0.5 {
+			return s.Offset + s.Amplitude
+		}
+		return s.Offset - s.Amplitude
+	default:
+		return s.Offset + s.Amplitude*math.Sin(2*math.Pi*phase)
+	}
+}
+
 func (n *triggerFloat) Delete() {
 	close(n.stop)
+	removeObjectLog(n)
+	removeObjectMetrics(n)
 	n.RemoveObjectFromRuntime()
 }
-
-func randFloat() float64 {
-	rand.NewSource(time.Now().UnixNano())
-	randomFloat := rand.Float64()*9 + 1
-	return float64(int(randomFloat))
-}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/NubeIO/reactive-nodes/constants"
+	"github.com/NubeIO/reactive-nodes/helpers/nodetest"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_waveValue(t *testing.T) {
+	s := &triggerSettings{Period: 1000, Amplitude: 2, Offset: 10}
+	testCases := []struct {
+		mode     triggerMode
+		elapsed  time.Duration
+		expected float64
+	}{
+		{triggerModeSine, 0, 10},
+		{triggerModeSine, 250 * time.Millisecond, 12},
+		{triggerModeSine, 750 * time.Millisecond, 8},
+		{triggerModeSawtooth, 0, 8},
+		{triggerModeSawtooth, 500 * time.Millisecond, 10},
+		{triggerModeSawtooth, 1500 * time.Millisecond, 10},
+		{triggerModeSquare, 100 * time.Millisecond, 12},
+		{triggerModeSquare, 600 * time.Millisecond, 8},
+	}
+	for _, testCase := range testCases {
+		s.Mode = testCase.mode
+		result := waveValue(s, testCase.elapsed)
+		if math.Abs(result-testCase.expected) > 1e-9 {
+			t.Errorf("Mode: %s, Elapsed: %v, Expected: %f, Got: %f", testCase.mode, testCase.elapsed, testCase.expected, result)
+		}
+	}
+}
+
+func Test_triggerCounter(t *testing.T) {
+	n := &triggerFloat{settings: &triggerSettings{Mode: triggerModeCounter, Step: 2}, count: 5}
+	for _, expected := range []float64{5, 7, 9} {
+		if result := n.nextValue(time.Now()); result != expected {
+			t.Errorf("Expected: %f, Got: %f", expected, result)
+		}
+	}
+}
+
+func Test_triggerSeeded(t *testing.T) {
+	s := &triggerSettings{Mode: triggerModeRandom, Seed: 7, Min: 1, Max: 10}
+	genA, _ := newTriggerGenerator(s)
+	genB, _ := newTriggerGenerator(s)
+	a := &triggerFloat{settings: s, generator: genA}
+	b := &triggerFloat{settings: s, generator: genB}
+	for i := 0; i < 10; i++ {
+		va, vb := a.nextValue(time.Now()), b.nextValue(time.Now())
+		if va != vb {
+			t.Fatalf("same seed gave different values: %f != %f", va, vb)
+		}
+		if va < 1 || va >= 10 {
+			t.Fatalf("value %f out of range", va)
+		}
+	}
+}
+
+func TestTriggerReplayMissingFile(t *testing.T) {
+	h := nodetest.New(t, &Trigger, map[string]any{"mode": "replay", "file": filepath.Join(t.TempDir(), "missing.csv"), "interval": 10}).Start()
+	h.ExpectNone(constants.Output, 50*time.Millisecond)
+}
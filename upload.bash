@@ -8,6 +8,8 @@ fi
 
 PLUGIN_PATH="$1"
 PLUGIN_NAME="$2.so"
+# the version is taken from the git tag, eg; v1.2.0 or v1.2.0-3-gabc1234-dirty between tags
+PLUGIN_VERSION=$(git -C "$PLUGIN_PATH" describe --tags --always --dirty 2>/dev/null || echo "v0.0.0-dev")
 
 # URL of the plugin upload endpoint
 URL="http://localhost:1770/api/plugins/upload?install=true"
@@ -22,7 +24,7 @@ cd "$PLUGIN_PATH"
 
 # Build the plugin
 echo "Building the plugin..."
-go build -ldflags "-s -w" -buildmode=plugin -o "$PLUGIN_NAME"  *go
+go build -ldflags "-s -w -X main.pluginName=$2 -X main.pluginVersion=$PLUGIN_VERSION" -buildmode=plugin -o "$PLUGIN_NAME"  *go
 
 if [ $? -ne 0 ]; then
     echo "Error: Failed to build the plugin."